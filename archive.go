@@ -0,0 +1,507 @@
+package partition
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// ArchiveFormat is file format of archived partition
+type ArchiveFormat string
+
+const (
+	// ArchiveCSV writes header and rows as CSV. NULL is written as \N and backslash in values is escaped as \\.
+	ArchiveCSV ArchiveFormat = "csv"
+	// ArchiveJSONLines writes a JSON object per row. values are written as string or null.
+	// values of binary columns are written in base64 in both formats.
+	ArchiveJSONLines ArchiveFormat = "jsonl"
+)
+
+// binaryTypes are data types of columns whose values are written in base64
+var binaryTypes = map[string]bool{
+	"binary": true, "varbinary": true, "tinyblob": true, "blob": true, "mediumblob": true, "longblob": true, "bit": true,
+	"geometry": true, "point": true, "linestring": true, "polygon": true,
+	"multipoint": true, "multilinestring": true, "multipolygon": true, "geometrycollection": true,
+}
+
+// Manifest describe archived partition. it is written next to the archive as NAME.manifest.json.
+// Base64 is columns of binary types whose values are encoded in base64.
+type Manifest struct {
+	Table       string        `json:"table"`
+	Partition   string        `json:"partition"`
+	Description string        `json:"description"`
+	File        string        `json:"file"`
+	Format      ArchiveFormat `json:"format"`
+	Compressed  bool          `json:"compressed"`
+	Columns     []string      `json:"columns"`
+	Base64      []string      `json:"base64,omitempty"`
+	Rows        int64         `json:"rows"`
+	SHA256      string        `json:"sha256"`
+	ArchivedAt  time.Time     `json:"archived_at"`
+}
+
+type archive struct {
+	dir      string
+	format   ArchiveFormat
+	compress bool
+}
+
+// Archive set Drops to write all rows of each partition into dir before dropping it.
+// the partition is dropped only after the archive is verified with its row count and checksum.
+// row counts are checked again just before DROP, but archiving and DROP are not atomic,
+// so writes into the partitions should be stopped before dropping them.
+// files are readable only by owner and existing archives are not overwritten.
+func Archive(dir string, format ArchiveFormat, compress bool) Option {
+	return func(p *partitioner) {
+		p.archive = &archive{
+			dir:      dir,
+			format:   format,
+			compress: compress,
+		}
+	}
+}
+
+// prepareArchives returns handlers which archive the partitions and manifests of archived partitions filled by executing them
+func (p *partitioner) prepareArchives(partitions ...*Partition) (handlers, map[string]*Manifest, error) {
+	if isNumbered(p.partBuilder) {
+		return nil, nil, fmt.Errorf("error archive is not supported for %s partition", p.partitionType)
+	}
+
+	if p.archive.format != ArchiveCSV && p.archive.format != ArchiveJSONLines {
+		return nil, nil, fmt.Errorf("error unknown archive format %s", p.archive.format)
+	}
+
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error quoteTable")
+	}
+
+	hs := handlers{}
+	archived := map[string]*Manifest{}
+	for _, partition := range partitions {
+		name, err := quoteName(partition.Name)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "error quoteName")
+		}
+
+		partition := partition
+		h := p.newHandler(fmt.Sprintf("SELECT * FROM %s PARTITION (%s)", table, name), "")
+		h.query = func(ctx context.Context, statement string) error {
+			manifest, err := p.archivePartition(ctx, partition, statement)
+			if err != nil {
+				return err
+			}
+			archived[partition.Name] = manifest
+			return nil
+		}
+		hs = append(hs, h)
+	}
+
+	return hs, archived, nil
+}
+
+// dropArchived checks number of rows of archived partitions is not changed and drops them
+func (p *partitioner) dropArchived(ctx context.Context, archived map[string]*Manifest, statement string) error {
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return errors.Wrap(err, "error quoteTable")
+	}
+
+	for name, manifest := range archived {
+		quoted, err := quoteName(name)
+		if err != nil {
+			return errors.Wrap(err, "error quoteName")
+		}
+
+		var count int64
+		if err := p.db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s PARTITION (%s)", table, quoted)).Scan(&count); err != nil {
+			return errors.Wrap(err, "error count rows")
+		}
+
+		if count != manifest.Rows {
+			return fmt.Errorf("error partition %s has %d rows, archived %d rows", name, count, manifest.Rows)
+		}
+	}
+
+	_, err = p.db.ExecContext(ctx, statement)
+	return err
+}
+
+// paths returns path of archive file and manifest. path separators in names are replaced.
+func (a *archive) paths(table, partition string) (string, string) {
+	name := strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(table + "." + partition)
+	ext := "." + string(a.format)
+	if a.compress {
+		ext += ".gz"
+	}
+
+	return filepath.Join(a.dir, name+ext), filepath.Join(a.dir, name+".manifest.json")
+}
+
+// archivePartition writes rows selected by statement into file, verifies it and writes manifest
+func (p *partitioner) archivePartition(ctx context.Context, partition *Partition, statement string) (*Manifest, error) {
+	a := p.archive
+	path, manifestPath := a.paths(p.qualifiedTable(), partition.Name)
+	tmp := path + ".tmp"
+
+	for _, target := range []string{path, manifestPath} {
+		if _, err := os.Lstat(target); err == nil {
+			return nil, fmt.Errorf("error archive %s already exists", target)
+		} else if !os.IsNotExist(err) {
+			return nil, errors.Wrap(err, "error stat archive")
+		}
+	}
+
+	manifest := &Manifest{
+		Table:      p.qualifiedTable(),
+		Partition:  partition.Name,
+		File:       filepath.Base(path),
+		Format:     a.format,
+		Compressed: a.compress,
+		ArchivedAt: p.now(),
+	}
+
+	desc, err := p.retrieveDescription(ctx, partition.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieveDescription")
+	}
+	manifest.Description = desc
+
+	binary, err := p.retrieveBinaryColumns(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieveBinaryColumns")
+	}
+
+	if err := p.writeArchive(ctx, tmp, statement, manifest, binary); err != nil {
+		os.Remove(tmp)
+		return nil, errors.Wrap(err, "error writeArchive")
+	}
+
+	if err := p.verifyArchive(ctx, tmp, statement, manifest); err != nil {
+		os.Remove(tmp)
+		return nil, errors.Wrap(err, "error verifyArchive")
+	}
+
+	// link fails instead of replacing archive created after the check
+	if err := os.Link(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, errors.Wrap(err, "error link archive")
+	}
+	os.Remove(tmp)
+
+	f, err := os.OpenFile(manifestPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "error create manifest")
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return nil, errors.Wrap(err, "error write manifest")
+	}
+
+	return manifest, f.Close()
+}
+
+// retrieveBinaryColumns returns columns of the table whose data types are binary
+func (p *partitioner) retrieveBinaryColumns(ctx context.Context) (map[string]bool, error) {
+	dbName, err := p.dbName(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error dbName")
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+SELECT
+  column_name,
+  data_type
+FROM
+  information_schema.COLUMNS
+WHERE
+  table_name	= ? AND
+  table_schema	= ?
+`, p.table, dbName)
+	if err != nil {
+		return nil, errors.Wrap(err, "error select columns")
+	}
+	defer rows.Close()
+
+	binary := map[string]bool{}
+	for rows.Next() {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			return nil, errors.Wrap(err, "error scan column")
+		}
+		if binaryTypes[strings.ToLower(typ)] {
+			binary[name] = true
+		}
+	}
+
+	return binary, rows.Err()
+}
+
+func (p *partitioner) writeArchive(ctx context.Context, path, statement string, manifest *Manifest, binary map[string]bool) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "error create archive")
+	}
+	defer f.Close()
+
+	sum := sha256.New()
+	var w io.Writer = io.MultiWriter(f, sum)
+	var gz *gzip.Writer
+	if manifest.Compressed {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+	buf := bufio.NewWriter(w)
+
+	rows, err := p.db.QueryContext(ctx, statement)
+	if err != nil {
+		return errors.Wrap(err, "error select rows")
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return errors.Wrap(err, "error columns")
+	}
+	manifest.Columns = columns
+
+	encoded := make([]bool, len(columns))
+	for i, column := range columns {
+		if binary[column] {
+			encoded[i] = true
+			manifest.Base64 = append(manifest.Base64, column)
+		}
+	}
+
+	writer, err := newRowWriter(buf, manifest.Format, columns, encoded)
+	if err != nil {
+		return err
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return errors.Wrap(err, "error scan row")
+		}
+		if err := writer.write(values); err != nil {
+			return errors.Wrap(err, "error write row")
+		}
+		manifest.Rows++
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "error rows")
+	}
+
+	if err := writer.flush(); err != nil {
+		return errors.Wrap(err, "error flush")
+	}
+
+	if err := buf.Flush(); err != nil {
+		return errors.Wrap(err, "error flush")
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return errors.Wrap(err, "error close gzip")
+		}
+	}
+	manifest.SHA256 = hex.EncodeToString(sum.Sum(nil))
+
+	return f.Close()
+}
+
+// verifyArchive reads archive again and checks checksum and number of rows with the partition
+func (p *partitioner) verifyArchive(ctx context.Context, path, statement string, manifest *Manifest) error {
+	rows, sum, err := readArchive(path, manifest.Format, manifest.Compressed)
+	if err != nil {
+		return errors.Wrap(err, "error readArchive")
+	}
+
+	if sum != manifest.SHA256 {
+		return fmt.Errorf("error checksum of archive %s is %s, expected %s", path, sum, manifest.SHA256)
+	}
+
+	var count int64
+	if err := p.db.QueryRowContext(ctx, strings.Replace(statement, "SELECT *", "SELECT COUNT(*)", 1)).Scan(&count); err != nil {
+		return errors.Wrap(err, "error count rows")
+	}
+
+	if rows != manifest.Rows || rows != count {
+		return fmt.Errorf("error archive %s has %d rows, partition %s has %d rows", path, rows, manifest.Partition, count)
+	}
+
+	return nil
+}
+
+// readArchive returns number of rows and checksum of archive
+func readArchive(path string, format ArchiveFormat, compressed bool) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", errors.Wrap(err, "error open archive")
+	}
+	defer f.Close()
+
+	sum := sha256.New()
+	tee := io.TeeReader(f, sum)
+	r := tee
+	if compressed {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return 0, "", errors.Wrap(err, "error open gzip")
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	rows, err := countRows(r, format)
+	if err != nil {
+		return 0, "", err
+	}
+
+	// read trailing bytes not consumed by decoder into checksum
+	if _, err := io.Copy(ioutil.Discard, tee); err != nil {
+		return 0, "", errors.Wrap(err, "error read archive")
+	}
+
+	return rows, hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+func countRows(r io.Reader, format ArchiveFormat) (int64, error) {
+	var rows int64
+	switch format {
+	case ArchiveCSV:
+		cr := csv.NewReader(r)
+		if _, err := cr.Read(); err != nil {
+			return 0, errors.Wrap(err, "error read header")
+		}
+		for {
+			_, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return 0, errors.Wrap(err, "error read csv")
+			}
+			rows++
+		}
+	case ArchiveJSONLines:
+		dec := json.NewDecoder(r)
+		for {
+			row := map[string]*string{}
+			err := dec.Decode(&row)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return 0, errors.Wrap(err, "error read json")
+			}
+			rows++
+		}
+	}
+
+	return rows, nil
+}
+
+type rowWriter interface {
+	write([]sql.RawBytes) error
+	flush() error
+}
+
+// newRowWriter returns writer of rows. values of encoded columns are written in base64.
+func newRowWriter(w io.Writer, format ArchiveFormat, columns []string, encoded []bool) (rowWriter, error) {
+	switch format {
+	case ArchiveCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return nil, errors.Wrap(err, "error write header")
+		}
+		return &csvWriter{writer: cw, encoded: encoded, record: make([]string, len(columns))}, nil
+	case ArchiveJSONLines:
+		return &jsonWriter{encoder: json.NewEncoder(w), columns: columns, encoded: encoded}, nil
+	}
+
+	return nil, fmt.Errorf("error unknown archive format %s", format)
+}
+
+type csvWriter struct {
+	writer  *csv.Writer
+	encoded []bool
+	record  []string
+}
+
+func (w *csvWriter) write(values []sql.RawBytes) error {
+	for i, v := range values {
+		switch {
+		case v == nil:
+			w.record[i] = `\N`
+		case w.encoded[i]:
+			w.record[i] = base64.StdEncoding.EncodeToString(v)
+		default:
+			// \N in values is distinguished from NULL
+			w.record[i] = strings.Replace(string(v), `\`, `\\`, -1)
+		}
+	}
+
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonWriter struct {
+	encoder *json.Encoder
+	columns []string
+	encoded []bool
+}
+
+func (w *jsonWriter) write(values []sql.RawBytes) error {
+	row := make(map[string]*string, len(values))
+	for i, v := range values {
+		if v == nil {
+			row[w.columns[i]] = nil
+			continue
+		}
+
+		s := string(v)
+		if w.encoded[i] {
+			s = base64.StdEncoding.EncodeToString(v)
+		} else if !utf8.Valid(v) {
+			// encoding/json replaces invalid bytes
+			return fmt.Errorf("error value of column %s is not valid utf8", w.columns[i])
+		}
+		row[w.columns[i]] = &s
+	}
+
+	return w.encoder.Encode(row)
+}
+
+func (w *jsonWriter) flush() error {
+	return nil
+}
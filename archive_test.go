@@ -0,0 +1,119 @@
+package partition
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrepareDropsWithArchive(t *testing.T) {
+	p := NewRangePartitioner(nil, "logs", "TO_DAYS(created_at)", Archive("/var/archive", ArchiveCSV, true))
+
+	h, err := p.PrepareDrops(NewPartition("p1", "", ""), NewPartition("p2", "", ""))
+	if err != nil {
+		t.Fatal("error prepare drops.", err.Error())
+	}
+
+	expect := "SELECT * FROM `logs` PARTITION (`p1`);\nSELECT * FROM `logs` PARTITION (`p2`);\nALTER TABLE `logs` DROP PARTITION `p1`,`p2`"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	if _, err := NewHashPartitioner(nil, "logs", "id", Archive("/var/archive", ArchiveCSV, false)).PrepareDrops(NewPartitions(1)...); err == nil {
+		t.Fatal("error archive of hash partition must be error.")
+	}
+
+	path, manifest := (&archive{dir: "/var/archive", format: ArchiveJSONLines}).paths("db.logs", "p/1")
+	if diff := cmp.Diff([]string{path, manifest}, []string{"/var/archive/db.logs.p_1.jsonl", "/var/archive/db.logs.p_1.manifest.json"}); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+}
+
+func Test_readArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal("error temp dir.", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	columns := []string{"id", "name", "data"}
+	encoded := []bool{false, false, true}
+	rows := [][]sql.RawBytes{
+		[]sql.RawBytes{sql.RawBytes("1"), sql.RawBytes("a,\"b\"\nc"), sql.RawBytes("\xff\x00")},
+		[]sql.RawBytes{sql.RawBytes("2"), nil, nil},
+		[]sql.RawBytes{sql.RawBytes("3"), sql.RawBytes(`\N`), sql.RawBytes{}},
+	}
+
+	type Test struct {
+		Format     ArchiveFormat
+		Compressed bool
+		Content    string
+	}
+
+	tests := []Test{
+		Test{Format: ArchiveCSV, Content: "id,name,data\n1,\"a,\"\"b\"\"\nc\",/wA=\n2,\\N,\\N\n3,\\\\N,\n"},
+		Test{Format: ArchiveJSONLines, Content: "{\"data\":\"/wA=\",\"id\":\"1\",\"name\":\"a,\\\"b\\\"\\nc\"}\n{\"data\":null,\"id\":\"2\",\"name\":null}\n{\"data\":\"\",\"id\":\"3\",\"name\":\"\\\\N\"}\n"},
+		Test{Format: ArchiveCSV, Compressed: true},
+	}
+
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		var gz *gzip.Writer
+		w, err := newRowWriter(buf, test.Format, columns, encoded)
+		if test.Compressed {
+			gz = gzip.NewWriter(buf)
+			w, err = newRowWriter(gz, test.Format, columns, encoded)
+		}
+		if err != nil {
+			t.Fatal("error new row writer.", err.Error())
+		}
+
+		for _, row := range rows {
+			if err := w.write(row); err != nil {
+				t.Fatal("error write.", err.Error())
+			}
+		}
+
+		if err := w.flush(); err != nil {
+			t.Fatal("error flush.", err.Error())
+		}
+
+		if gz != nil {
+			gz.Close()
+		} else if diff := cmp.Diff(buf.String(), test.Content); diff != "" {
+			t.Fatalf("error invalid content:%s", diff)
+		}
+
+		sum := sha256.Sum256(buf.Bytes())
+		path := filepath.Join(dir, "archive."+string(test.Format))
+		if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal("error write file.", err.Error())
+		}
+
+		count, checksum, err := readArchive(path, test.Format, test.Compressed)
+		if err != nil {
+			t.Fatal("error read archive.", err.Error())
+		}
+
+		if count != 3 || checksum != hex.EncodeToString(sum[:]) {
+			t.Fatalf("error invalid result. rows:%d checksum:%s", count, checksum)
+		}
+	}
+
+	w, err := newRowWriter(&bytes.Buffer{}, ArchiveJSONLines, columns, encoded)
+	if err != nil {
+		t.Fatal("error new row writer.", err.Error())
+	}
+
+	if err := w.write([]sql.RawBytes{sql.RawBytes("4"), sql.RawBytes("\xff"), nil}); err == nil {
+		t.Fatal("error invalid utf8 must be error.")
+	}
+}
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Konboi/go-mysql-partition"
+	"github.com/Konboi/go-mysql-partition/config"
+)
+
+const usage = `Usage: mysql-partition [options] <command> [arguments]
+
+Commands:
+  list                          show partitions of the table
+  is-partitioned                exit 0 if the table is partitioned, 1 if not and 2 on error
+  create <partition>...         partition the table
+  add <partition>...            add partitions
+  drop <partition>...           drop partitions
+  truncate <partition>...       truncate partitions
+  reorganize <from> <partition>...
+                                reorganize comma separated partitions <from> into partitions
+  repartition <partition>...    partition the table again by -type and -expression
+  remove-partitioning           make the table not partitioned
+  analyze [<partition>...]      analyze partitions, all partitions if not specified
+  check [<partition>...]        check partitions
+  optimize [<partition>...]     optimize partitions
+  repair [<partition>...]       repair partitions
+  rebuild [<partition>...]      rebuild partitions
+  rotate                        add future partitions and drop expired partitions.
+                                with -config, rotate all tables which have interval
+
+Partition is specified as NAME=DESCRIPTION (e.g. p20240101=2024-01-01 or p1=1,2).
+For hash and key partition, a number N means N partitions.
+
+Options:
+`
+
+type options struct {
+	config     string
+	dsn        string
+	schema     string
+	table      string
+	typ        string
+	expression string
+	catchAll   string
+	linear     bool
+	dryrun     bool
+	verbose    bool
+
+	showCreateTable bool
+
+	migrateDir    string
+	migrateFormat string
+
+	interval  string
+	layout    string
+	lookAhead int
+	retention int
+
+	archiveDir    string
+	archiveFormat string
+	archiveGzip   bool
+
+	protect         string
+	refuseNonEmpty  bool
+	refuseNewerThan time.Duration
+	maxPartitions   int
+	force           bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	fs := flag.NewFlagSet("mysql-partition", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.dsn, "dsn", os.Getenv("MYSQL_PARTITION_DSN"), "data source name of go-sql-driver/mysql. default $MYSQL_PARTITION_DSN")
+	fs.StringVar(&opts.config, "config", "", "YAML or TOML config file describing partition policies of tables")
+	fs.StringVar(&opts.table, "table", "", "table name. schema.table is also accepted")
+	fs.StringVar(&opts.schema, "schema", "", "schema of the table. default schema of -dsn")
+	fs.StringVar(&opts.typ, "type", "range", "partition type: range, range columns, list, list columns, hash or key")
+	fs.StringVar(&opts.expression, "expression", "", "partition expression or columns")
+	fs.StringVar(&opts.catchAll, "catch-all", "", "catch all partition name of range partition")
+	fs.BoolVar(&opts.linear, "linear", false, "use linear hash or linear key partition")
+	fs.BoolVar(&opts.dryrun, "dry-run", false, "print statements without executing")
+	fs.BoolVar(&opts.verbose, "verbose", false, "print executed statements")
+	fs.BoolVar(&opts.showCreateTable, "show-create-table", false, "read partitions from SHOW CREATE TABLE instead of information_schema")
+	fs.StringVar(&opts.interval, "interval", "monthly", "rotate interval: hourly, daily, weekly, monthly or yearly")
+	fs.StringVar(&opts.layout, "layout", "", "time layout of partition name for rotate. default depends on interval")
+	fs.IntVar(&opts.lookAhead, "look-ahead", 1, "number of future intervals to be prepared by rotate")
+	fs.IntVar(&opts.retention, "retention", 0, "number of past intervals to be kept by rotate. 0 means never drop")
+	fs.StringVar(&opts.archiveDir, "archive-dir", "", "directory to archive rows of partitions before drop")
+	fs.StringVar(&opts.archiveFormat, "archive-format", "csv", "archive format: csv or jsonl")
+	fs.BoolVar(&opts.archiveGzip, "archive-gzip", false, "compress archive with gzip")
+	fs.StringVar(&opts.protect, "protect", "", "comma separated partitions never to be dropped or truncated")
+	fs.BoolVar(&opts.refuseNonEmpty, "refuse-non-empty", false, "refuse to drop or truncate partitions which have rows")
+	fs.DurationVar(&opts.refuseNewerThan, "refuse-newer-than", -1, "refuse to drop or truncate range partitions whose upper bound is newer than now minus the duration")
+	fs.IntVar(&opts.maxPartitions, "max-partitions", 0, "refuse to drop or truncate more partitions at once. 0 means no limit")
+	fs.StringVar(&opts.migrateDir, "migrate-dir", "", "write statements as migration files into the directory instead of executing them. not supported by analyze, check, optimize and repair")
+	fs.StringVar(&opts.migrateFormat, "migrate-format", "golang-migrate", "migration format: golang-migrate, goose or plain")
+	fs.BoolVar(&opts.force, "force", false, "override -protect, -refuse-non-empty, -refuse-newer-than and -max-partitions")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if opts.dsn == "" || (opts.table == "" && opts.config == "") {
+		fmt.Fprintln(stderr, "mysql-partition: -dsn and -table or -config are required")
+		return 2
+	}
+
+	if opts.config != "" && fs.Arg(0) != "rotate" {
+		fmt.Fprintln(stderr, "mysql-partition: -config supports only rotate command")
+		return 2
+	}
+
+	if _, err := partition.ParseMigrationFormat(opts.migrateFormat); err != nil {
+		fmt.Fprintln(stderr, "mysql-partition:", err)
+		return 2
+	}
+
+	var c *config.Config
+	if opts.config != "" {
+		var err error
+		if c, err = config.Load(opts.config); err != nil {
+			fmt.Fprintln(stderr, "mysql-partition:", err)
+			return 2
+		}
+	}
+
+	// is-partitioned exits with 1 when the table is not partitioned
+	failure := 1
+	if fs.Arg(0) == "is-partitioned" {
+		failure = 2
+	}
+
+	db, err := sql.Open("mysql", opts.dsn)
+	if err != nil {
+		fmt.Fprintln(stderr, "mysql-partition: error open database:", err)
+		return failure
+	}
+	defer db.Close()
+
+	if c != nil {
+		if err := rotateAll(db, c, opts, stdout, stderr); err != nil {
+			return 1
+		}
+		return 0
+	}
+
+	p, err := newPartitioner(db, opts)
+	if err != nil {
+		fmt.Fprintln(stderr, "mysql-partition:", err)
+		return 2
+	}
+
+	if err := execute(p, opts, fs.Arg(0), fs.Args()[1:], stdout); err != nil {
+		if err == errNotPartitioned {
+			return 1
+		}
+		fmt.Fprintf(stderr, "mysql-partition: %s: %s\n", fs.Arg(0), err)
+		return failure
+	}
+
+	return 0
+}
+
+var errNotPartitioned = fmt.Errorf("not partitioned")
+
+// partitioner is capabilities of partitioners used by commands
+type partitioner interface {
+	partition.Partitioner
+	partition.Inspector
+	partition.Reorganizer
+	partition.Repartitioner
+	partition.Maintainer
+}
+
+func newPartitioner(db *sql.DB, opts *options) (partitioner, error) {
+	typ := strings.ToUpper(strings.Join(strings.Fields(opts.typ), " "))
+	options := []partition.Option{
+		partition.Dryrun(opts.dryrun),
+		partition.Verbose(opts.verbose),
+		partition.CatchAllPartitionName(opts.catchAll),
+		partition.Schema(opts.schema),
+		partition.ShowCreateTable(opts.showCreateTable),
+	}
+	options = append(options, archiveOptions(opts)...)
+	options = append(options, guardOptions(opts)...)
+
+	// type of range and list partitioner is determined by Type and type of hash and key partitioner by Linear
+	typed := append([]partition.Option{partition.Type(typ)}, options...)
+	// -type "linear hash" is same as -type hash -linear
+	linear := append([]partition.Option{partition.Linear(opts.linear || strings.HasPrefix(typ, "LINEAR "))}, options...)
+
+	var p partition.Partitioner
+	switch {
+	case strings.HasPrefix(typ, partition.PartitionTypeRange):
+		p = partition.NewRangePartitioner(db, opts.table, opts.expression, typed...)
+	case strings.HasPrefix(typ, partition.PartitionTypeList):
+		p = partition.NewListPartitioner(db, opts.table, opts.expression, typed...)
+	case strings.HasSuffix(typ, partition.PartitionTypeHash):
+		p = partition.NewHashPartitioner(db, opts.table, opts.expression, linear...)
+	case strings.HasSuffix(typ, partition.PartitionTypeKey):
+		p = partition.NewKeyPartitioner(db, opts.table, opts.expression, linear...)
+	default:
+		return nil, fmt.Errorf("unknown partition type %s", opts.typ)
+	}
+
+	return p.(partitioner), nil
+}
+
+func execute(p partitioner, opts *options, command string, args []string, stdout io.Writer) error {
+	switch command {
+	case "list":
+		return list(p, stdout)
+	case "is-partitioned":
+		partitioned, err := p.IsPartitioned()
+		if err != nil {
+			return err
+		}
+		if !partitioned {
+			return errNotPartitioned
+		}
+		return nil
+	case "rotate":
+		r, ok := p.(partition.RangePartitioner)
+		if !ok || !strings.HasPrefix(strings.ToUpper(opts.typ), partition.PartitionTypeRange) {
+			return fmt.Errorf("rotate is supported only for range partition")
+		}
+		interval, err := partition.ParseInterval(opts.interval)
+		if err != nil {
+			return err
+		}
+		rotateOptions := []partition.RotateOption{partition.LookAhead(opts.lookAhead), partition.Retention(opts.retention)}
+		if opts.layout != "" {
+			rotateOptions = append(rotateOptions, partition.NameLayout(opts.layout))
+		}
+		h, err := partition.NewRotator(r, interval, rotateOptions...).Prepare()
+		if err != nil {
+			return err
+		}
+		return apply(h, opts, command+" "+opts.table, stdout)
+	case "remove-partitioning":
+		h, err := p.PrepareRemovePartitioning()
+		if err != nil {
+			return err
+		}
+		return apply(h, opts, command+" "+opts.table, stdout)
+	case "reorganize":
+		if len(args) < 2 {
+			return fmt.Errorf("from partitions and into partitions are required")
+		}
+		from := []*partition.Partition{}
+		for _, name := range strings.Split(args[0], ",") {
+			from = append(from, partition.NewPartition(name, "", ""))
+		}
+		into, err := parsePartitions(args[1:])
+		if err != nil {
+			return err
+		}
+		h, err := p.PrepareReorganizes(from, into)
+		if err != nil {
+			return err
+		}
+		return apply(h, opts, command+" "+opts.table, stdout)
+	case "rebuild":
+		partitions, err := parsePartitions(args)
+		if err != nil {
+			return err
+		}
+		h, err := p.PrepareRebuilds(partitions...)
+		if err != nil {
+			return err
+		}
+		return apply(h, opts, command+" "+opts.table, stdout)
+	}
+
+	maintenances := map[string]func(...*partition.Partition) ([]*partition.MaintenanceResult, error){
+		"analyze":  p.Analyzes,
+		"check":    p.Checks,
+		"optimize": p.Optimizes,
+		"repair":   p.Repairs,
+	}
+
+	if maintenance, ok := maintenances[command]; ok {
+		// result set of the statement can't be written as migration
+		if opts.migrateDir != "" {
+			return fmt.Errorf("-migrate-dir is not supported")
+		}
+		partitions, err := parsePartitions(args)
+		if err != nil {
+			return err
+		}
+		results, err := maintenance(partitions...)
+		if err != nil {
+			return err
+		}
+		return printResults(results, stdout)
+	}
+
+	operations := map[string]func(...*partition.Partition) (partition.Handler, error){
+		"create":      p.PrepareCreates,
+		"add":         p.PrepareAdds,
+		"drop":        p.PrepareDrops,
+		"truncate":    p.PrepareTruncates,
+		"repartition": p.PrepareRepartition,
+	}
+
+	operation, ok := operations[command]
+	if !ok {
+		return fmt.Errorf("unknown command")
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("partitions are required")
+	}
+
+	partitions, err := parsePartitions(args)
+	if err != nil {
+		return err
+	}
+
+	h, err := operation(partitions...)
+	if err != nil {
+		return err
+	}
+
+	return apply(h, opts, command+" "+opts.table, stdout)
+}
+
+// apply executes the handler, or writes it as migration files with -migrate-dir
+func apply(h partition.Handler, opts *options, name string, stdout io.Writer) error {
+	if opts.migrateDir == "" {
+		return h.Execute()
+	}
+
+	format, err := partition.ParseMigrationFormat(opts.migrateFormat)
+	if err != nil {
+		return err
+	}
+
+	paths, err := partition.NewExporter(opts.migrateDir, format).Export(name, h)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		fmt.Fprintln(stdout, path)
+	}
+
+	return nil
+}
+
+// rotateAll rotates all tables in config and reports each failure
+func rotateAll(db *sql.DB, c *config.Config, opts *options, stdout, stderr io.Writer) error {
+	var failed error
+	for _, t := range c.Tables {
+		options := append([]partition.Option{partition.Dryrun(opts.dryrun), partition.Verbose(opts.verbose), partition.ShowCreateTable(opts.showCreateTable)}, archiveOptions(opts)...)
+		options = append(options, guardOptions(opts)...)
+		r, err := t.Rotator(db, options...)
+		if err == nil && r != nil {
+			var h partition.Handler
+			if h, err = r.Prepare(); err == nil {
+				err = apply(h, opts, "rotate "+t.Table, stdout)
+			}
+		}
+
+		if err != nil {
+			fmt.Fprintf(stderr, "mysql-partition: rotate: %s: %s\n", t.Table, err)
+			failed = err
+		}
+	}
+
+	return failed
+}
+
+func guardOptions(opts *options) []partition.Option {
+	options := []partition.Option{}
+	if opts.protect != "" {
+		options = append(options, partition.Protect(strings.Split(opts.protect, ",")...))
+	}
+	if opts.refuseNonEmpty {
+		options = append(options, partition.RefuseNonEmpty())
+	}
+	if 0 <= opts.refuseNewerThan {
+		options = append(options, partition.RefuseNewerThan(opts.refuseNewerThan))
+	}
+	if 0 < opts.maxPartitions {
+		options = append(options, partition.MaxPartitions(opts.maxPartitions))
+	}
+	if opts.force {
+		options = append(options, partition.Force(true))
+	}
+
+	return options
+}
+
+func archiveOptions(opts *options) []partition.Option {
+	if opts.archiveDir == "" {
+		return nil
+	}
+
+	return []partition.Option{partition.Archive(opts.archiveDir, partition.ArchiveFormat(opts.archiveFormat), opts.archiveGzip)}
+}
+
+func list(p partitioner, stdout io.Writer) error {
+	infos, err := p.Partitions()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tMETHOD\tDESCRIPTION\tROWS\tDATA_LENGTH\tINDEX_LENGTH\tCOMMENT")
+	for _, info := range infos {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n", info.Name, info.Method, info.Description, info.TableRows, info.DataLength, info.IndexLength, info.Comment)
+	}
+
+	return w.Flush()
+}
+
+func printResults(results []*partition.MaintenanceResult, stdout io.Writer) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tPARTITION\tOP\tMSG_TYPE\tMSG_TEXT")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Table, r.Partition, r.Op, r.MsgType, r.MsgText)
+	}
+
+	return w.Flush()
+}
+
+// parsePartitions parses NAME=DESCRIPTION or number of partitions
+func parsePartitions(args []string) ([]*partition.Partition, error) {
+	partitions := []*partition.Partition{}
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n <= 0 {
+				return nil, fmt.Errorf("invalid number of partitions %s", arg)
+			}
+			partitions = append(partitions, partition.NewPartitions(n)...)
+			continue
+		}
+
+		kv := strings.SplitN(arg, "=", 2)
+		if kv[0] == "" {
+			return nil, fmt.Errorf("invalid partition %q", arg)
+		}
+
+		description := ""
+		if len(kv) == 2 {
+			description = kv[1]
+		}
+		partitions = append(partitions, partition.NewPartition(kv[0], description, ""))
+	}
+
+	return partitions, nil
+}
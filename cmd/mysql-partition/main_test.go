@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Konboi/go-mysql-partition"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePartitions(t *testing.T) {
+	partitions, err := parsePartitions([]string{"p20240101=2024-01-01", "p1=1,2", "p2", "2"})
+	if err != nil {
+		t.Fatal("error parse partitions.", err.Error())
+	}
+
+	expect := []*partition.Partition{
+		partition.NewPartition("p20240101", "2024-01-01", ""),
+		partition.NewPartition("p1", "1,2", ""),
+		partition.NewPartition("p2", "", ""),
+		&partition.Partition{},
+		&partition.Partition{},
+	}
+
+	if diff := cmp.Diff(partitions, expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	if _, err := parsePartitions([]string{"=1"}); err == nil {
+		t.Fatal("error empty name must be error.")
+	}
+
+	if _, err := parsePartitions([]string{"0"}); err == nil {
+		t.Fatal("error zero partitions must be error.")
+	}
+}
+
+func TestRun(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	if code := run([]string{}, stdout, stderr); code != 2 {
+		t.Fatalf("error invalid exit code. got:%d want:%d", code, 2)
+	}
+
+	if code := run([]string{"-dsn", "root@/test", "list"}, stdout, stderr); code != 2 {
+		t.Fatalf("error invalid exit code. got:%d want:%d", code, 2)
+	}
+
+	if code := run([]string{"-dsn", "root@/test", "-table", "t", "-type", "columns", "list"}, stdout, stderr); code != 2 {
+		t.Fatalf("error invalid exit code. got:%d want:%d", code, 2)
+	}
+
+	if code := run([]string{"-dsn", "root@/test", "-config", "partition.yml", "list"}, stdout, stderr); code != 2 {
+		t.Fatalf("error invalid exit code. got:%d want:%d", code, 2)
+	}
+
+	if code := run([]string{"-dsn", "root@/test", "-table", "t", "-migrate-format", "flyway", "list"}, stdout, stderr); code != 2 {
+		t.Fatalf("error invalid exit code. got:%d want:%d", code, 2)
+	}
+
+	// error is distinguished from not partitioned table
+	if code := run([]string{"-dsn", "root@tcp(127.0.0.1:1)/test", "-table", "t", "is-partitioned"}, stdout, stderr); code != 2 {
+		t.Fatalf("error invalid exit code. got:%d want:%d", code, 2)
+	}
+}
+
+func TestNewPartitioner(t *testing.T) {
+	type Test struct {
+		Title  string
+		Opts   *options
+		Output string
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "linear hash by type",
+			Opts:   &options{table: "users", typ: "linear hash", expression: "user_id"},
+			Output: "ALTER TABLE `users` PARTITION BY LINEAR HASH (user_id) PARTITIONS 2",
+		},
+		Test{
+			Title:  "linear key by flag",
+			Opts:   &options{table: "users", typ: "key", expression: "user_id", linear: true},
+			Output: "ALTER TABLE `users` PARTITION BY LINEAR KEY (user_id) PARTITIONS 2",
+		},
+		Test{
+			Title:  "hash",
+			Opts:   &options{table: "users", typ: "hash", expression: "user_id"},
+			Output: "ALTER TABLE `users` PARTITION BY HASH (user_id) PARTITIONS 2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			p, err := newPartitioner(nil, test.Opts)
+			if err != nil {
+				t.Fatal("error new partitioner.", err.Error())
+			}
+
+			h, err := p.PrepareCreates(partition.NewPartitions(2)...)
+			if err != nil {
+				t.Fatal("error prepare creates.", err.Error())
+			}
+
+			if diff := cmp.Diff(h.Statement(), test.Output); diff != "" {
+				t.Fatalf("error invalid result:%s", diff)
+			}
+		})
+	}
+
+	p, err := newPartitioner(nil, &options{table: "logs", typ: "range  columns", expression: "created_at", catchAll: "pmax"})
+	if err != nil {
+		t.Fatal("error new partitioner.", err.Error())
+	}
+
+	h, err := p.PrepareCreates(partition.NewPartition("p1", "2024-01-01", ""))
+	if err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	expect := "ALTER TABLE `logs` PARTITION BY RANGE COLUMNS (created_at) (PARTITION `p1` VALUES LESS THAN ('2024-01-01'), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+}
+
+func TestExecuteMigrateDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration")
+	if err != nil {
+		t.Fatal("error temp dir.", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	opts := &options{table: "logs", typ: "list", expression: "event_id", migrateDir: dir, migrateFormat: "goose"}
+	p, err := newPartitioner(nil, opts)
+	if err != nil {
+		t.Fatal("error new partitioner.", err.Error())
+	}
+
+	stdout := &bytes.Buffer{}
+	if err := execute(p, opts, "rebuild", []string{"p1"}, stdout); err != nil {
+		t.Fatal("error execute.", err.Error())
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*_rebuild_logs.sql"))
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("error rebuild must be written as migration. paths:%v", paths)
+	}
+
+	for _, command := range []string{"analyze", "check", "optimize", "repair"} {
+		if err := execute(p, opts, command, nil, stdout); err == nil {
+			t.Fatalf("error %s with -migrate-dir must be error.", command)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Package config loads partition policies of many tables from YAML or TOML file
+// and builds partitioners for them.
+//
+//	tables:
+//	  - schema: analytics
+//	    table: events
+//	    type: range columns
+//	    expression: created_at
+//	    catch_all: pmax
+//	    interval: monthly
+//	    look_ahead: 3
+//	    retention: 12
+//	    name_layout: p200601
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/Konboi/go-mysql-partition"
+	"github.com/pkg/errors"
+)
+
+// Config describe partition policies of tables
+type Config struct {
+	Tables []*Table
+}
+
+// Table describe partition policy of a table
+type Table struct {
+	Schema     string
+	Table      string
+	Type       string
+	Expression string
+	CatchAll   string
+	Interval   string
+	LookAhead  *int
+	Retention  int
+	NameLayout string
+
+	// Line is line number of the table in config file
+	Line  int
+	lines map[string]int
+}
+
+// Error is config error with position
+type Error struct {
+	File    string
+	Line    int
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	pos := ""
+	switch {
+	case e.File != "" && e.Line != 0:
+		pos = fmt.Sprintf("%s:%d: ", e.File, e.Line)
+	case e.File != "":
+		pos = e.File + ": "
+	case e.Line != 0:
+		pos = fmt.Sprintf("line %d: ", e.Line)
+	}
+
+	if e.Field != "" {
+		return fmt.Sprintf("%s%s: %s", pos, e.Field, e.Message)
+	}
+
+	return pos + e.Message
+}
+
+// Load loads config file. format is decided by extension: .yml, .yaml or .toml.
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error read config")
+	}
+
+	var c *Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		c, err = ParseYAML(data)
+	case ".toml":
+		c, err = ParseTOML(data)
+	default:
+		return nil, fmt.Errorf("error unknown config format %s", path)
+	}
+
+	if e, ok := err.(*Error); ok {
+		e.File = path
+	}
+
+	return c, err
+}
+
+// Validate checks policies and returns *Error pointing to the offending line
+func (c *Config) Validate() error {
+	if len(c.Tables) == 0 {
+		return &Error{Message: "no table is specified"}
+	}
+
+	seen := map[string]bool{}
+	for i, t := range c.Tables {
+		if err := t.validate(); err != nil {
+			err.Field = fmt.Sprintf("tables[%d].%s", i, err.Field)
+			return err
+		}
+
+		name := t.name()
+		if seen[name] {
+			return &Error{Line: t.Line, Field: fmt.Sprintf("tables[%d]", i), Message: fmt.Sprintf("table %s is duplicated", name)}
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
+
+func (t *Table) errorf(field, format string, args ...interface{}) *Error {
+	line := t.lines[field]
+	if line == 0 {
+		line = t.Line
+	}
+
+	return &Error{Line: line, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+func (t *Table) isRange() bool {
+	return strings.HasPrefix(strings.ToUpper(t.Type), partition.PartitionTypeRange)
+}
+
+func (t *Table) validate() *Error {
+	if t.Table == "" {
+		return t.errorf("table", "table is required")
+	}
+
+	typ := strings.ToUpper(strings.Join(strings.Fields(t.Type), " "))
+	switch typ {
+	case "RANGE", "RANGE COLUMNS", "LIST", "LIST COLUMNS", "HASH", "LINEAR HASH":
+		if t.Expression == "" {
+			return t.errorf("expression", "expression is required for %s partition", t.Type)
+		}
+	case "KEY", "LINEAR KEY":
+	case "":
+		return t.errorf("type", "type is required")
+	default:
+		return t.errorf("type", "unknown partition type %q", t.Type)
+	}
+
+	if t.CatchAll != "" && !t.isRange() {
+		return t.errorf("catch_all", "catch all partition is supported only for range partition")
+	}
+
+	if t.Interval == "" {
+		for _, field := range []string{"look_ahead", "retention", "name_layout"} {
+			if t.lines[field] != 0 {
+				return t.errorf(field, "%s requires interval", field)
+			}
+		}
+		return nil
+	}
+
+	if !t.isRange() {
+		return t.errorf("interval", "interval is supported only for range partition")
+	}
+
+	if _, err := partition.ParseInterval(t.Interval); err != nil {
+		return t.errorf("interval", "unknown interval %q", t.Interval)
+	}
+
+	// boundaries of rotated partitions are dates, which RANGE partition can compare only through these functions
+	if err := partition.CheckTimeValue(typ, t.Expression); err != nil {
+		return t.errorf("interval", "interval needs range columns or expression by TO_DAYS, TO_SECONDS, UNIX_TIMESTAMP or YEAR")
+	}
+
+	if t.LookAhead != nil && *t.LookAhead < 0 {
+		return t.errorf("look_ahead", "look_ahead must not be negative")
+	}
+
+	if t.Retention < 0 {
+		return t.errorf("retention", "retention must not be negative")
+	}
+
+	return nil
+}
+
+func (t *Table) name() string {
+	if t.Schema == "" {
+		return t.Table
+	}
+
+	return t.Schema + "." + t.Table
+}
+
+// Partitioner builds partitioner of the table. table is qualified with schema if specified.
+func (t *Table) Partitioner(db *sql.DB, options ...partition.Option) (partition.Partitioner, error) {
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+
+	options = append([]partition.Option{partition.CatchAllPartitionName(t.CatchAll)}, options...)
+
+	typ := strings.ToUpper(strings.Join(strings.Fields(t.Type), " "))
+	switch typ {
+	case "RANGE", "RANGE COLUMNS":
+		return partition.NewRangePartitioner(db, t.name(), t.Expression, append([]partition.Option{partition.Type(typ)}, options...)...), nil
+	case "LIST", "LIST COLUMNS":
+		return partition.NewListPartitioner(db, t.name(), t.Expression, append([]partition.Option{partition.Type(typ)}, options...)...), nil
+	case "HASH", "LINEAR HASH":
+		return partition.NewHashPartitioner(db, t.name(), t.Expression, append([]partition.Option{partition.Linear(typ != "HASH")}, options...)...), nil
+	}
+
+	return partition.NewKeyPartitioner(db, t.name(), t.Expression, append([]partition.Option{partition.Linear(typ != "KEY")}, options...)...), nil
+}
+
+// Rotator builds rotator of the table. it returns nil when interval isn't specified.
+func (t *Table) Rotator(db *sql.DB, options ...partition.Option) (*partition.Rotator, error) {
+	if t.Interval == "" {
+		return nil, nil
+	}
+
+	p, err := t.Partitioner(db, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, err := partition.ParseInterval(t.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	rotateOptions := []partition.RotateOption{partition.Retention(t.Retention)}
+	if t.LookAhead != nil {
+		rotateOptions = append(rotateOptions, partition.LookAhead(*t.LookAhead))
+	}
+	if t.NameLayout != "" {
+		rotateOptions = append(rotateOptions, partition.NameLayout(t.NameLayout))
+	}
+
+	return partition.NewRotator(p.(partition.RangePartitioner), interval, rotateOptions...), nil
+}
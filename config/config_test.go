@@ -0,0 +1,218 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Konboi/go-mysql-partition"
+	"github.com/google/go-cmp/cmp"
+)
+
+const yamlConfig = `tables:
+  - schema: analytics
+    table: events
+    type: range columns
+    expression: created_at
+    catch_all: pmax
+    interval: monthly
+    look_ahead: 3
+    retention: 12
+    name_layout: p200601
+  - table: users
+    type: hash
+    expression: user_id
+`
+
+const tomlConfig = `[[tables]]
+schema = "analytics"
+table = "events"
+type = "range columns"
+expression = "created_at"
+catch_all = "pmax"
+interval = "monthly"
+look_ahead = 3
+retention = 12
+name_layout = "p200601"
+
+[[tables]]
+table = "users"
+type = "hash"
+expression = "user_id"
+`
+
+func TestParse(t *testing.T) {
+	lookAhead := 3
+	expect := []*Table{
+		&Table{
+			Schema:     "analytics",
+			Table:      "events",
+			Type:       "range columns",
+			Expression: "created_at",
+			CatchAll:   "pmax",
+			Interval:   "monthly",
+			LookAhead:  &lookAhead,
+			Retention:  12,
+			NameLayout: "p200601",
+		},
+		&Table{
+			Table:      "users",
+			Type:       "hash",
+			Expression: "user_id",
+		},
+	}
+
+	opt := cmp.FilterPath(func(path cmp.Path) bool {
+		name := path.Last().String()
+		return name == ".Line" || name == ".lines"
+	}, cmp.Ignore())
+
+	tests := []struct {
+		Title string
+		Parse func([]byte) (*Config, error)
+		Input string
+		Line  int
+	}{
+		{Title: "yaml", Parse: ParseYAML, Input: yamlConfig, Line: 11},
+		{Title: "toml", Parse: ParseTOML, Input: tomlConfig, Line: 12},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			c, err := test.Parse([]byte(test.Input))
+			if err != nil {
+				t.Fatal("error parse.", err.Error())
+			}
+
+			if diff := cmp.Diff(c.Tables, expect, opt); diff != "" {
+				t.Fatalf("error invalid result:%s", diff)
+			}
+
+			if c.Tables[1].Line != test.Line {
+				t.Fatalf("error invalid line. got:%d want:%d", c.Tables[1].Line, test.Line)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	type Test struct {
+		Title  string
+		Parse  func([]byte) (*Config, error)
+		Input  string
+		Output string
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "unknown interval",
+			Parse:  ParseYAML,
+			Input:  "tables:\n  - table: events\n    type: range\n    expression: TO_DAYS(created_at)\n    interval: minutely\n",
+			Output: "line 5: tables[0].interval: unknown interval \"minutely\"",
+		},
+		Test{
+			Title:  "interval without time function",
+			Parse:  ParseYAML,
+			Input:  "tables:\n  - table: events\n    type: range\n    expression: id\n    interval: daily\n",
+			Output: "line 5: tables[0].interval: interval needs range columns or expression by TO_DAYS, TO_SECONDS, UNIX_TIMESTAMP or YEAR",
+		},
+		Test{
+			Title:  "unknown field",
+			Parse:  ParseYAML,
+			Input:  "tables:\n  - table: events\n    typo: range\n",
+			Output: "line 3: tables[0].typo: unknown field",
+		},
+		Test{
+			Title:  "invalid value",
+			Parse:  ParseYAML,
+			Input:  "tables:\n  - table: events\n    type: range\n    expression: id\n    interval: daily\n    retention: forever\n",
+			Output: "line 6: tables[0].retention: invalid value \"forever\"",
+		},
+		Test{
+			Title:  "catch all for list",
+			Parse:  ParseTOML,
+			Input:  "[[tables]]\ntable = \"events\"\ntype = \"list\"\nexpression = \"id\"\ncatch_all = \"pmax\"\n",
+			Output: "line 5: tables[0].catch_all: catch all partition is supported only for range partition",
+		},
+		Test{
+			Title:  "duplicated table",
+			Parse:  ParseTOML,
+			Input:  "[[tables]]\ntable = \"events\"\ntype = \"key\"\n\n[[tables]]\ntable = \"events\"\ntype = \"hash\"\nexpression = \"id\"\n",
+			Output: "line 5: tables[1]: table events is duplicated",
+		},
+		Test{
+			Title:  "retention without interval",
+			Parse:  ParseTOML,
+			Input:  "[[tables]]\ntable = \"events\"\ntype = \"range\"\nexpression = \"id\"\nretention = 3\n",
+			Output: "line 5: tables[0].retention: retention requires interval",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			_, err := test.Parse([]byte(test.Input))
+			if err == nil {
+				t.Fatal("error must be error.")
+			}
+
+			if diff := cmp.Diff(err.Error(), test.Output); diff != "" {
+				t.Fatalf("error invalid result:%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal("error temp dir.", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "partition.yml")
+	if err := ioutil.WriteFile(path, []byte("tables:\n  - table: events\n"), 0644); err != nil {
+		t.Fatal("error write config.", err.Error())
+	}
+
+	_, err = Load(path)
+	if err == nil {
+		t.Fatal("error must be error.")
+	}
+
+	if diff := cmp.Diff(err.Error(), path+":2: tables[0].type: type is required"); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(yamlConfig), 0644); err != nil {
+		t.Fatal("error write config.", err.Error())
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal("error load.", err.Error())
+	}
+
+	r, err := c.Tables[0].Rotator(nil)
+	if err != nil {
+		t.Fatal("error rotator.", err.Error())
+	}
+
+	if r == nil {
+		t.Fatal("error rotator must be built.")
+	}
+
+	p, err := c.Tables[1].Partitioner(nil)
+	if err != nil {
+		t.Fatal("error partitioner.", err.Error())
+	}
+
+	h, err := p.PrepareCreates(partition.NewPartitions(4)...)
+	if err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	if diff := cmp.Diff(h.Statement(), "ALTER TABLE `users` PARTITION BY HASH (user_id) PARTITIONS 4"); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+}
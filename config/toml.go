@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/pelletier/go-toml"
+)
+
+// ParseTOML parses and validates TOML config.
+//
+//	[[tables]]
+//	table = "events"
+//	type = "range columns"
+//	expression = "created_at"
+func ParseTOML(data []byte) (*Config, error) {
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return nil, &Error{Message: err.Error()}
+	}
+
+	for _, key := range tree.Keys() {
+		if key != "tables" {
+			return nil, &Error{Line: tree.GetPosition(key).Line, Field: key, Message: "unknown field"}
+		}
+	}
+
+	c := &Config{}
+	switch tables := tree.Get("tables").(type) {
+	case nil:
+	case []*toml.Tree:
+		for i, table := range tables {
+			t, err := parseTOMLTable(table)
+			if err != nil {
+				err.Field = fmt.Sprintf("tables[%d].%s", i, err.Field)
+				return nil, err
+			}
+			c.Tables = append(c.Tables, t)
+		}
+	default:
+		return nil, &Error{Line: tree.GetPosition("tables").Line, Field: "tables", Message: "tables must be array of tables"}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func parseTOMLTable(tree *toml.Tree) (*Table, *Error) {
+	t := &Table{Line: tree.Position().Line, lines: map[string]int{}}
+	for _, key := range tree.Keys() {
+		line := tree.GetPosition(key).Line
+		t.lines[key] = line
+
+		value := tree.Get(key)
+		var ok bool
+		switch key {
+		case "schema":
+			t.Schema, ok = value.(string)
+		case "table":
+			t.Table, ok = value.(string)
+		case "type":
+			t.Type, ok = value.(string)
+		case "expression":
+			t.Expression, ok = value.(string)
+		case "catch_all":
+			t.CatchAll, ok = value.(string)
+		case "interval":
+			t.Interval, ok = value.(string)
+		case "look_ahead":
+			var n int64
+			n, ok = value.(int64)
+			lookAhead := int(n)
+			t.LookAhead = &lookAhead
+		case "retention":
+			var n int64
+			n, ok = value.(int64)
+			t.Retention = int(n)
+		case "name_layout":
+			t.NameLayout, ok = value.(string)
+		default:
+			return nil, &Error{Line: line, Field: key, Message: "unknown field"}
+		}
+
+		if !ok {
+			return nil, &Error{Line: line, Field: key, Message: fmt.Sprintf("invalid value %v", value)}
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML parses and validates YAML config
+func ParseYAML(data []byte) (*Config, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, &Error{Message: err.Error()}
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, &Error{Message: "config is empty"}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &Error{Line: root.Line, Message: "config must be mapping"}
+	}
+
+	c := &Config{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "tables" {
+			return nil, &Error{Line: key.Line, Field: key.Value, Message: "unknown field"}
+		}
+
+		if value.Kind != yaml.SequenceNode {
+			return nil, &Error{Line: value.Line, Field: "tables", Message: "tables must be sequence"}
+		}
+
+		for j, node := range value.Content {
+			t, err := parseYAMLTable(node)
+			if err != nil {
+				err.Field = fmt.Sprintf("tables[%d].%s", j, err.Field)
+				return nil, err
+			}
+			c.Tables = append(c.Tables, t)
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func parseYAMLTable(node *yaml.Node) (*Table, *Error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, &Error{Line: node.Line, Message: "table must be mapping"}
+	}
+
+	t := &Table{Line: node.Line, lines: map[string]int{}}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		t.lines[key.Value] = value.Line
+
+		var err error
+		switch key.Value {
+		case "schema":
+			err = value.Decode(&t.Schema)
+		case "table":
+			err = value.Decode(&t.Table)
+		case "type":
+			err = value.Decode(&t.Type)
+		case "expression":
+			err = value.Decode(&t.Expression)
+		case "catch_all":
+			err = value.Decode(&t.CatchAll)
+		case "interval":
+			err = value.Decode(&t.Interval)
+		case "look_ahead":
+			err = value.Decode(&t.LookAhead)
+		case "retention":
+			err = value.Decode(&t.Retention)
+		case "name_layout":
+			err = value.Decode(&t.NameLayout)
+		default:
+			return nil, &Error{Line: key.Line, Field: key.Value, Message: "unknown field"}
+		}
+
+		if err != nil {
+			return nil, &Error{Line: value.Line, Field: key.Value, Message: fmt.Sprintf("invalid value %q", value.Value)}
+		}
+	}
+
+	return t, nil
+}
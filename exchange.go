@@ -0,0 +1,283 @@
+package partition
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Exchanger exchanges partition with not partitioned table
+type Exchanger interface {
+	CreateExchangeTable(table string) error
+	ExchangePartition(partition *Partition, table string, withValidation bool) error
+
+	CreateExchangeTableContext(ctx context.Context, table string) error
+	ExchangePartitionContext(ctx context.Context, partition *Partition, table string, withValidation bool) error
+
+	PrepareCreateExchangeTable(table string) (Handler, error)
+	PrepareExchangePartition(partition *Partition, table string, withValidation bool) (Handler, error)
+
+	PrepareCreateExchangeTableContext(ctx context.Context, table string) (Handler, error)
+	PrepareExchangePartitionContext(ctx context.Context, partition *Partition, table string, withValidation bool) (Handler, error)
+}
+
+// column describe column metadata in information_schema.COLUMNS compared for exchange partition
+type column struct {
+	Name      string
+	Type      string
+	Nullable  string
+	Collation string
+	Extra     string
+}
+
+// index describe index column metadata in information_schema.STATISTICS compared for exchange partition
+type index struct {
+	Name      string
+	Column    string
+	NonUnique int
+}
+
+func (p *partitioner) CreateExchangeTable(table string) error {
+	return p.CreateExchangeTableContext(context.Background(), table)
+}
+
+func (p *partitioner) CreateExchangeTableContext(ctx context.Context, table string) error {
+	h, err := p.PrepareCreateExchangeTableContext(ctx, table)
+	if err != nil {
+		return errors.Wrap(err, "error PrepareCreateExchangeTable")
+	}
+	return h.ExecuteContext(ctx)
+}
+
+func (p *partitioner) PrepareCreateExchangeTable(table string) (Handler, error) {
+	return p.PrepareCreateExchangeTableContext(context.Background(), table)
+}
+
+// PrepareCreateExchangeTableContext prepares non partitioned table which has same structure as the partitioned table
+func (p *partitioner) PrepareCreateExchangeTableContext(ctx context.Context, table string) (Handler, error) {
+	source, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return nil, errors.Wrap(err, "error quoteTable")
+	}
+
+	target, err := quoteTable(table)
+	if err != nil {
+		return nil, errors.Wrap(err, "error quoteTable")
+	}
+
+	return handlers{
+		p.newHandler(fmt.Sprintf("CREATE TABLE %s LIKE %s", target, source), ""),
+		p.newHandler(fmt.Sprintf("ALTER TABLE %s REMOVE PARTITIONING", target), ""),
+	}, nil
+}
+
+func (p *partitioner) ExchangePartition(partition *Partition, table string, withValidation bool) error {
+	return p.ExchangePartitionContext(context.Background(), partition, table, withValidation)
+}
+
+func (p *partitioner) ExchangePartitionContext(ctx context.Context, partition *Partition, table string, withValidation bool) error {
+	h, err := p.PrepareExchangePartitionContext(ctx, partition, table, withValidation)
+	if err != nil {
+		return errors.Wrap(err, "error PrepareExchangePartition")
+	}
+	return h.ExecuteContext(ctx)
+}
+
+func (p *partitioner) PrepareExchangePartition(partition *Partition, table string, withValidation bool) (Handler, error) {
+	return p.PrepareExchangePartitionContext(context.Background(), partition, table, withValidation)
+}
+
+// PrepareExchangePartitionContext prepares to swap rows of the partition with non partitioned table.
+// withValidation makes mysql check every row of the table belongs to the partition.
+// structure of the tables is checked before when db is available.
+func (p *partitioner) PrepareExchangePartitionContext(ctx context.Context, partition *Partition, table string, withValidation bool) (Handler, error) {
+	stmt, err := p.buildExchangeSQL(partition, table, withValidation)
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildExchangeSQL")
+	}
+
+	if p.db != nil {
+		if err := p.validateExchange(ctx, partition, table); err != nil {
+			return nil, errors.Wrap(err, "error validateExchange")
+		}
+	}
+
+	// exchange is reverted by exchanging again
+	return p.newHandler(stmt, stmt), nil
+}
+
+func (p *partitioner) buildExchangeSQL(partition *Partition, table string, withValidation bool) (string, error) {
+	source, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	name, err := quoteName(partition.Name)
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteName")
+	}
+
+	target, err := quoteTable(table)
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	validation := "WITHOUT VALIDATION"
+	if withValidation {
+		validation = "WITH VALIDATION"
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s EXCHANGE PARTITION %s WITH TABLE %s %s", source, name, target, validation), nil
+}
+
+// validateExchange checks the partition exists and the table is not partitioned and has same columns and indexes
+func (p *partitioner) validateExchange(ctx context.Context, partition *Partition, table string) error {
+	has, err := p.HasPartitionContext(ctx, partition)
+	if err != nil {
+		return errors.Wrap(err, "error HasPartition")
+	}
+	if !has {
+		return fmt.Errorf("error partition %s does not exist", partition.Name)
+	}
+
+	dbName, err := p.dbName(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error dbName")
+	}
+
+	sourceSchema, source := dbName, p.table
+
+	targetSchema, target := splitTable(table)
+	if targetSchema == "" {
+		targetSchema = dbName
+	}
+
+	var partitioned int
+	if err := p.db.QueryRowContext(ctx, `
+SELECT
+  COUNT(*)
+FROM
+  information_schema.PARTITIONS
+WHERE
+  table_name		= ? AND
+  table_schema		= ? AND
+  partition_name IS NOT NULL
+`, target, targetSchema).Scan(&partitioned); err != nil {
+		return errors.Wrap(err, "error select partitions")
+	}
+
+	v := &validator{}
+	if 0 < partitioned {
+		v.conflict(partition.Name, table, "table is partitioned")
+	}
+
+	sourceColumns, err := p.retrieveColumns(ctx, sourceSchema, source)
+	if err != nil {
+		return errors.Wrap(err, "error retrieveColumns")
+	}
+
+	targetColumns, err := p.retrieveColumns(ctx, targetSchema, target)
+	if err != nil {
+		return errors.Wrap(err, "error retrieveColumns")
+	}
+
+	if len(targetColumns) == 0 {
+		return fmt.Errorf("error table %s does not exist", table)
+	}
+
+	if len(sourceColumns) != len(targetColumns) {
+		v.conflict(partition.Name, table, "number of columns %d is different from %d", len(targetColumns), len(sourceColumns))
+	} else {
+		for i := range sourceColumns {
+			if *sourceColumns[i] != *targetColumns[i] {
+				v.conflict(partition.Name, table, "column %s %s is different from %s %s", targetColumns[i].Name, targetColumns[i].Type, sourceColumns[i].Name, sourceColumns[i].Type)
+			}
+		}
+	}
+
+	sourceIndexes, err := p.retrieveIndexes(ctx, sourceSchema, source)
+	if err != nil {
+		return errors.Wrap(err, "error retrieveIndexes")
+	}
+
+	targetIndexes, err := p.retrieveIndexes(ctx, targetSchema, target)
+	if err != nil {
+		return errors.Wrap(err, "error retrieveIndexes")
+	}
+
+	if len(sourceIndexes) != len(targetIndexes) {
+		v.conflict(partition.Name, table, "indexes are different")
+	} else {
+		for i := range sourceIndexes {
+			if *sourceIndexes[i] != *targetIndexes[i] {
+				v.conflict(partition.Name, table, "index %s is different", sourceIndexes[i].Name)
+				break
+			}
+		}
+	}
+
+	if 0 < len(v.conflicts) {
+		return &ValidationError{Conflicts: v.conflicts}
+	}
+
+	return nil
+}
+
+func (p *partitioner) retrieveColumns(ctx context.Context, schema, table string) ([]*column, error) {
+	rows, err := p.db.QueryContext(ctx, `
+SELECT
+  column_name, column_type, is_nullable, IFNULL(collation_name, ''), extra
+FROM
+  information_schema.COLUMNS
+WHERE
+  table_name		= ? AND
+  table_schema		= ?
+ORDER BY
+  ordinal_position
+`, table, schema)
+	if err != nil {
+		return nil, errors.Wrap(err, "error select columns")
+	}
+	defer rows.Close()
+
+	columns := []*column{}
+	for rows.Next() {
+		c := &column{}
+		if err := rows.Scan(&c.Name, &c.Type, &c.Nullable, &c.Collation, &c.Extra); err != nil {
+			return nil, errors.Wrap(err, "error scan column")
+		}
+		columns = append(columns, c)
+	}
+
+	return columns, rows.Err()
+}
+
+func (p *partitioner) retrieveIndexes(ctx context.Context, schema, table string) ([]*index, error) {
+	rows, err := p.db.QueryContext(ctx, `
+SELECT
+  index_name, column_name, non_unique
+FROM
+  information_schema.STATISTICS
+WHERE
+  table_name		= ? AND
+  table_schema		= ?
+ORDER BY
+  index_name, seq_in_index
+`, table, schema)
+	if err != nil {
+		return nil, errors.Wrap(err, "error select indexes")
+	}
+	defer rows.Close()
+
+	indexes := []*index{}
+	for rows.Next() {
+		i := &index{}
+		if err := rows.Scan(&i.Name, &i.Column, &i.NonUnique); err != nil {
+			return nil, errors.Wrap(err, "error scan index")
+		}
+		indexes = append(indexes, i)
+	}
+
+	return indexes, rows.Err()
+}
@@ -0,0 +1,41 @@
+package partition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrepareExchangePartition(t *testing.T) {
+	p := NewRangePartitioner(nil, "events", "TO_DAYS(created_at)").(Exchanger)
+
+	h, err := p.PrepareCreateExchangeTable("archive.events_202401")
+	if err != nil {
+		t.Fatal("error prepare create exchange table.", err.Error())
+	}
+
+	expect := "CREATE TABLE `archive`.`events_202401` LIKE `events`;\nALTER TABLE `archive`.`events_202401` REMOVE PARTITIONING"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	tests := map[bool]string{
+		true:  "ALTER TABLE `events` EXCHANGE PARTITION `p202401` WITH TABLE `archive`.`events_202401` WITH VALIDATION",
+		false: "ALTER TABLE `events` EXCHANGE PARTITION `p202401` WITH TABLE `archive`.`events_202401` WITHOUT VALIDATION",
+	}
+
+	for withValidation, expect := range tests {
+		h, err := p.PrepareExchangePartition(NewPartition("p202401", "", ""), "archive.events_202401", withValidation)
+		if err != nil {
+			t.Fatal("error prepare exchange partition.", err.Error())
+		}
+
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result:%s", diff)
+		}
+	}
+
+	if _, err := p.PrepareExchangePartition(NewPartition("p202401", "", ""), "", true); err == nil {
+		t.Fatal("error empty table must be error.")
+	}
+}
@@ -0,0 +1,270 @@
+package partition
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Explainer reports partitions scanned by queries
+type Explainer interface {
+	ExplainPartitions(query string, args ...interface{}) ([]string, error)
+	AssertPrunedTo(query string, args []interface{}, expected []string) error
+
+	ExplainPartitionsContext(ctx context.Context, query string, args ...interface{}) ([]string, error)
+	AssertPrunedToContext(ctx context.Context, query string, args []interface{}, expected []string) error
+}
+
+// ExplainPartitions returns partitions of the table scanned by the query, which are reported by EXPLAIN.
+// subpartitions are reported as PARTITION_SUBPARTITION.
+// the table may be aliased in the query. error is returned when the query doesn't refer the table.
+func (p *partitioner) ExplainPartitions(query string, args ...interface{}) ([]string, error) {
+	return p.ExplainPartitionsContext(context.Background(), query, args...)
+}
+
+func (p *partitioner) ExplainPartitionsContext(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	partitions, ok, err := p.explain(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error explain")
+	}
+
+	// partitions column is reported only by EXPLAIN PARTITIONS before mysql 5.7
+	if !ok {
+		partitions, ok, err = p.explain(ctx, "EXPLAIN PARTITIONS "+query, args...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error explain partitions")
+		}
+		if !ok {
+			return nil, fmt.Errorf("error EXPLAIN has no partitions column")
+		}
+	}
+
+	return partitions, nil
+}
+
+// explain returns partitions of rows for the table.
+// EXPLAIN and SHOW WARNINGS run in a transaction to be executed on the same connection.
+// the rewritten query in the warning tells schema and alias of tables in the plan.
+// only name of the table is compared when the warning is not reported such as EXPLAIN PARTITIONS of mysql 5.6.
+func (p *partitioner) explain(ctx context.Context, query string, args ...interface{}) ([]string, bool, error) {
+	dbName, err := p.dbName(ctx)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error dbName")
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error begin")
+	}
+	defer tx.Rollback()
+
+	plan, ok, err := explainRows(ctx, tx, query, args...)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	names, referred, err := explainedNames(ctx, tx, dbName, p.table)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error explainedNames")
+	}
+
+	found := false
+	matched := []string{}
+	for _, row := range plan {
+		if !names[row.table.String] {
+			continue
+		}
+		found = true
+
+		if row.partitions.Valid {
+			matched = append(matched, strings.Split(row.partitions.String, ",")...)
+		}
+	}
+
+	// all partitions are pruned when the table is referred but not accessed such as impossible WHERE
+	if !found && !referred {
+		return nil, false, fmt.Errorf("error table %s.%s is not found in the plan", dbName, p.table)
+	}
+
+	return uniqueNames(matched), true, nil
+}
+
+type explainRow struct {
+	table      sql.NullString
+	partitions sql.NullString
+}
+
+// explainRows returns table and partitions of rows of the plan. ok is false when partitions column is missing.
+func explainRows(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]*explainRow, bool, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error query")
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error columns")
+	}
+
+	tableIndex, partitionsIndex := -1, -1
+	for i, column := range columns {
+		switch strings.ToLower(column) {
+		case "table":
+			tableIndex = i
+		case "partitions":
+			partitionsIndex = i
+		}
+	}
+
+	if tableIndex < 0 || partitionsIndex < 0 {
+		return nil, false, nil
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	plan := []*explainRow{}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, false, errors.Wrap(err, "error scan")
+		}
+		plan = append(plan, &explainRow{table: values[tableIndex], partitions: values[partitionsIndex]})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, false, errors.Wrap(err, "error rows")
+	}
+
+	return plan, true, nil
+}
+
+// explainedNames returns names which the table is referred as in the plan.
+// referred is true when the rewritten query is reported and refers the table.
+func explainedNames(ctx context.Context, tx *sql.Tx, schema, table string) (map[string]bool, bool, error) {
+	rows, err := tx.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error show warnings")
+	}
+	defer rows.Close()
+
+	var rewritten sql.NullString
+	for rows.Next() {
+		var level, message sql.NullString
+		var code int
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			return nil, false, errors.Wrap(err, "error scan warning")
+		}
+
+		// note of the query rewritten by optimizer
+		if code == 1003 {
+			rewritten = message
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, false, errors.Wrap(err, "error rows")
+	}
+
+	if !rewritten.Valid {
+		return map[string]bool{table: true}, false, nil
+	}
+
+	names := referredNames(rewritten.String, schema, table)
+	return names, 0 < len(names), nil
+}
+
+var referenceRegexp *regexp.Regexp
+
+func init() {
+	referenceRegexp = regexp.MustCompile("(?i)(?:from|join)\\s+`((?:[^`]|``)+)`\\.`((?:[^`]|``)+)`(?:\\s+`((?:[^`]|``)+)`)?")
+}
+
+// referredNames returns names of the table in rewritten query such as
+// select `db`.`e`.`id` AS `id` from `db`.`events` `e` join `db`.`users`
+func referredNames(rewritten, schema, table string) map[string]bool {
+	names := map[string]bool{}
+	for _, m := range referenceRegexp.FindAllStringSubmatch(rewritten, -1) {
+		if unquoteIdentifier(m[1]) != schema || unquoteIdentifier(m[2]) != table {
+			continue
+		}
+
+		name := unquoteIdentifier(m[2])
+		if m[3] != "" {
+			name = unquoteIdentifier(m[3])
+		}
+		names[name] = true
+	}
+
+	return names
+}
+
+func uniqueNames(names []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, name := range names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unique = append(unique, name)
+	}
+
+	return unique
+}
+
+// AssertPrunedTo returns error unless the query scans exactly the expected partitions of the table.
+// subpartitions scanned are counted as their partition.
+func (p *partitioner) AssertPrunedTo(query string, args []interface{}, expected []string) error {
+	return p.AssertPrunedToContext(context.Background(), query, args, expected)
+}
+
+func (p *partitioner) AssertPrunedToContext(ctx context.Context, query string, args []interface{}, expected []string) error {
+	scanned, err := p.ExplainPartitionsContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error ExplainPartitions")
+	}
+
+	infos, err := p.PartitionsContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error Partitions")
+	}
+
+	return comparePruned(scanned, expected, infos)
+}
+
+func comparePruned(scanned, expected []string, infos []*PartitionInfo) error {
+	parents := map[string]string{}
+	for _, info := range infos {
+		for _, sub := range info.Subpartitions {
+			parents[info.Name+"_"+sub.Name] = info.Name
+		}
+	}
+
+	actual := []string{}
+	for _, name := range scanned {
+		if parent, ok := parents[name]; ok {
+			name = parent
+		}
+		actual = append(actual, name)
+	}
+
+	actual = uniqueNames(actual)
+	want := uniqueNames(expected)
+	sort.Strings(actual)
+	sort.Strings(want)
+
+	if strings.Join(actual, ",") != strings.Join(want, ",") {
+		return fmt.Errorf("error query scans partitions [%s], expected [%s]", strings.Join(actual, ","), strings.Join(want, ","))
+	}
+
+	return nil
+}
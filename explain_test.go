@@ -0,0 +1,90 @@
+package partition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_uniqueNames(t *testing.T) {
+	result := uniqueNames([]string{"p1", "p2", "", "p1", "p3"})
+	if diff := cmp.Diff(result, []string{"p1", "p2", "p3"}); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+}
+
+func Test_referredNames(t *testing.T) {
+	type Test struct {
+		Title  string
+		Query  string
+		Output map[string]bool
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "aliased",
+			Query:  "/* select#1 */ select `test`.`e`.`id` AS `id` from `test`.`events` `e` where (`test`.`e`.`event_id` = 1)",
+			Output: map[string]bool{"e": true},
+		},
+		Test{
+			Title:  "join",
+			Query:  "/* select#1 */ select `test`.`users`.`id` AS `id` from `test`.`users` join `test`.`events` where (`test`.`events`.`user_id` = `test`.`users`.`id`)",
+			Output: map[string]bool{"events": true},
+		},
+		Test{
+			Title:  "other schema",
+			Query:  "/* select#1 */ select `archive`.`events`.`id` AS `id` from `archive`.`events`",
+			Output: map[string]bool{},
+		},
+		Test{
+			Title:  "self join",
+			Query:  "/* select#1 */ select 1 AS `1` from `test`.`events` `a` join `test`.`events` `b`",
+			Output: map[string]bool{"a": true, "b": true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			if diff := cmp.Diff(referredNames(test.Query, "test", "events"), test.Output); diff != "" {
+				t.Fatalf("error invalid result:%s", diff)
+			}
+		})
+	}
+}
+
+func Test_comparePruned(t *testing.T) {
+	type Test struct {
+		Title    string
+		Scanned  []string
+		Expected []string
+		Infos    []*PartitionInfo
+		Error    bool
+	}
+
+	infos := []*PartitionInfo{
+		&PartitionInfo{Name: "p1", Subpartitions: []*SubpartitionInfo{&SubpartitionInfo{Name: "p1sp0"}, &SubpartitionInfo{Name: "p1sp1"}}},
+		&PartitionInfo{Name: "p2", Subpartitions: []*SubpartitionInfo{&SubpartitionInfo{Name: "p2sp0"}, &SubpartitionInfo{Name: "p2sp1"}}},
+	}
+
+	tests := []Test{
+		Test{Title: "same", Scanned: []string{"p1", "p2"}, Expected: []string{"p2", "p1"}},
+		Test{Title: "more", Scanned: []string{"p1", "p2"}, Expected: []string{"p1"}, Error: true},
+		Test{Title: "less", Scanned: []string{"p1"}, Expected: []string{"p1", "p2"}, Error: true},
+		Test{Title: "none", Scanned: []string{}, Expected: []string{}},
+		Test{Title: "subpartitions", Scanned: []string{"p1_p1sp0", "p1_p1sp1"}, Expected: []string{"p1"}, Infos: infos},
+		Test{Title: "other subpartitions", Scanned: []string{"p1_p1sp0", "p2_p2sp1"}, Expected: []string{"p1"}, Infos: infos, Error: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			err := comparePruned(test.Scanned, test.Expected, test.Infos)
+			if test.Error && err == nil {
+				t.Fatal("error must be error.")
+			}
+
+			if !test.Error && err != nil {
+				t.Fatal("error compare pruned.", err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package partition
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GuardError is returned by PrepareDrops and PrepareTruncates when guard options refuse the statement.
+// use errors.Cause to retrieve it from returned error and Force to override guards.
+//
+// All guards apply to DROP and TRUNCATE of any partition type except that RefuseNewerThan checks only range partitions.
+// COALESCE of hash and key partitions is checked only by MaxPartitions because rows are kept and partitions are unnamed.
+type GuardError struct {
+	Operation  string
+	Violations []*Conflict
+}
+
+func (e *GuardError) Error() string {
+	violations := []string{}
+	for _, v := range e.Violations {
+		violations = append(violations, v.String())
+	}
+
+	return fmt.Sprintf("error %s is refused by guard. %s", e.Operation, strings.Join(violations, ", "))
+}
+
+type guard struct {
+	nonEmpty      bool
+	newerThan     *time.Duration
+	maxPartitions int
+	protected     map[string]bool
+	force         bool
+}
+
+func (p *partitioner) guardOption(f func(g *guard)) {
+	if p.guard == nil {
+		p.guard = &guard{
+			protected: map[string]bool{},
+		}
+	}
+	f(p.guard)
+}
+
+// RefuseNonEmpty refuses to drop or truncate partitions which have rows. coalesce of hash and key partitions isn't refused.
+func RefuseNonEmpty() Option {
+	return func(p *partitioner) {
+		p.guardOption(func(g *guard) {
+			g.nonEmpty = true
+		})
+	}
+}
+
+// RefuseNewerThan refuses to drop or truncate range partitions whose upper bound is later than now minus d.
+// RefuseNewerThan(0) protects partitions of current and future.
+func RefuseNewerThan(d time.Duration) Option {
+	return func(p *partitioner) {
+		p.guardOption(func(g *guard) {
+			g.newerThan = &d
+		})
+	}
+}
+
+// MaxPartitions refuses to drop, truncate or coalesce more than n partitions at once
+func MaxPartitions(n int) Option {
+	return func(p *partitioner) {
+		p.guardOption(func(g *guard) {
+			g.maxPartitions = n
+		})
+	}
+}
+
+// Protect refuses to drop or truncate the partitions. coalesce of hash and key partitions isn't refused.
+func Protect(names ...string) Option {
+	return func(p *partitioner) {
+		p.guardOption(func(g *guard) {
+			for _, name := range names {
+				g.protected[name] = true
+			}
+		})
+	}
+}
+
+// Force overrides guard options
+func Force(force bool) Option {
+	return func(p *partitioner) {
+		p.guardOption(func(g *guard) {
+			g.force = force
+		})
+	}
+}
+
+// checkGuard checks partitions to be dropped, truncated or coalesced.
+// rows and boundaries are checked only when db is available.
+func (p *partitioner) checkGuard(ctx context.Context, operation string, partitions []*Partition) error {
+	g := p.guard
+	if g == nil || g.force {
+		return nil
+	}
+
+	// coalesced partitions are unnamed and their rows are moved into remaining partitions
+	names := partitionNames(partitions)
+	if operation == "COALESCE" {
+		names = ""
+	}
+
+	v := &validator{}
+	if 0 < g.maxPartitions && g.maxPartitions < len(partitions) {
+		v.conflict(names, "", "%d partitions exceed max %d", len(partitions), g.maxPartitions)
+	}
+
+	if operation == "COALESCE" {
+		partitions = nil
+	}
+
+	for _, partition := range partitions {
+		if g.protected[partition.Name] {
+			v.conflict(partition.Name, "", "partition is protected")
+		}
+
+		if g.newerThan != nil {
+			if err := p.checkBoundary(ctx, v, partition); err != nil {
+				return errors.Wrap(err, "error checkBoundary")
+			}
+		}
+
+		if g.nonEmpty && p.db != nil {
+			empty, err := p.isEmpty(ctx, partition)
+			if err != nil {
+				return errors.Wrap(err, "error isEmpty")
+			}
+			if !empty {
+				v.conflict(partition.Name, "", "partition has rows")
+			}
+		}
+	}
+
+	if 0 < len(v.conflicts) {
+		return &GuardError{Operation: operation, Violations: v.conflicts}
+	}
+
+	return nil
+}
+
+func partitionNames(partitions []*Partition) string {
+	names := []string{}
+	for _, partition := range partitions {
+		names = append(names, partition.Name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (p *partitioner) checkBoundary(ctx context.Context, v *validator, partition *Partition) error {
+	if !strings.HasPrefix(p.partitionType, PartitionTypeRange) {
+		return nil
+	}
+
+	desc := partition.Description
+	if p.db != nil {
+		d, err := p.retrieveDescription(ctx, partition.Name)
+		if err != nil {
+			return errors.Wrap(err, "error retrieveDescription")
+		}
+		desc = d
+	}
+
+	if desc == "" {
+		return nil
+	}
+
+	limit := p.now().Add(-*p.guard.newerThan)
+	boundary, ok := boundaryTime(desc, p.expression)
+	switch {
+	case !ok:
+		v.conflict(partition.Name, "", "upper bound %s is not comparable with time", desc)
+	case boundary.After(limit):
+		v.conflict(partition.Name, "", "upper bound %s is newer than %s", desc, limit.Format("2006-01-02 15:04:05"))
+	}
+
+	return nil
+}
+
+// boundaryTime returns upper bound of range partition as time.
+// MAXVALUE is treated as far future.
+func boundaryTime(description, expression string) (time.Time, bool) {
+	values := splitColumns(description)
+	if len(values) == 0 {
+		return time.Time{}, false
+	}
+
+	b := parseValue(values[0])
+	switch {
+	case b.kind == boundMax:
+		return time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), true
+	case b.date:
+		t, err := time.Parse("2006-01-02 15:04:05.000000", b.text)
+		return t, err == nil
+	case b.kind != boundNumber || !b.number.IsInt():
+		return time.Time{}, false
+	}
+
+	n, err := strconv.ParseInt(b.number.RatString(), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	m := timeFunctionRegexp.FindStringSubmatch(expression)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	switch strings.ToUpper(m[1]) {
+	case "TO_DAYS":
+		return time.Unix((n-toDaysOfUnixEpoch)*86400, 0).UTC(), true
+	case "TO_SECONDS":
+		return time.Unix(n-toDaysOfUnixEpoch*86400, 0).UTC(), true
+	case "UNIX_TIMESTAMP":
+		return time.Unix(n, 0).UTC(), true
+	case "YEAR":
+		return time.Date(int(n), 1, 1, 0, 0, 0, 0, time.UTC), true
+	}
+
+	return time.Time{}, false
+}
+
+func (p *partitioner) isEmpty(ctx context.Context, partition *Partition) (bool, error) {
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return false, errors.Wrap(err, "error quoteTable")
+	}
+
+	name, err := quoteName(partition.Name)
+	if err != nil {
+		return false, errors.Wrap(err, "error quoteName")
+	}
+
+	var exists int
+	if err := p.db.QueryRowContext(ctx, fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s PARTITION (%s))", table, name)).Scan(&exists); err != nil {
+		return false, errors.Wrap(err, "error select rows")
+	}
+
+	return exists == 0, nil
+}
@@ -0,0 +1,105 @@
+package partition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestGuard(t *testing.T) {
+	now := func() time.Time {
+		return time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	}
+
+	p := NewRangePartitioner(nil, "events", "TO_DAYS(created_at)", Protect("pmax"), MaxPartitions(2), RefuseNewerThan(30*24*time.Hour))
+	p.(*partitioner).clock = now
+
+	partitions := []*Partition{
+		NewPartition("p202401", "739282", ""),
+		NewPartition("p202403", "TO_DAYS('2024-03-01')", ""),
+		NewPartition("pmax", "MAXVALUE", ""),
+	}
+
+	_, err := p.PrepareDrops(partitions...)
+	gerr, ok := errors.Cause(err).(*GuardError)
+	if !ok {
+		t.Fatalf("error invalid error: %v", err)
+	}
+
+	expect := []*Conflict{
+		&Conflict{Partition: "p202401,p202403,pmax", Reason: "3 partitions exceed max 2"},
+		&Conflict{Partition: "p202403", Reason: "upper bound TO_DAYS('2024-03-01') is newer than 2024-02-14 12:00:00"},
+		&Conflict{Partition: "pmax", Reason: "partition is protected"},
+		&Conflict{Partition: "pmax", Reason: "upper bound MAXVALUE is newer than 2024-02-14 12:00:00"},
+	}
+	if diff := cmp.Diff(gerr.Violations, expect); diff != "" {
+		t.Fatalf("error invalid violations:%s", diff)
+	}
+
+	if gerr.Operation != "DROP" {
+		t.Fatalf("error invalid operation %s.", gerr.Operation)
+	}
+
+	if _, err := p.PrepareTruncates(partitions[0]); err != nil {
+		t.Fatal("error old partition must be allowed.", err.Error())
+	}
+
+	Force(true)(p.(*partitioner))
+	if _, err := p.PrepareDrops(partitions...); err != nil {
+		t.Fatal("error forced drop must be allowed.", err.Error())
+	}
+
+	h := NewHashPartitioner(nil, "events", "id", MaxPartitions(2), RefuseNonEmpty(), RefuseNewerThan(0))
+	if _, err := h.PrepareDrops(NewPartitions(2)...); err != nil {
+		t.Fatal("error coalesce within max partitions must be allowed.", err.Error())
+	}
+
+	_, err = h.PrepareDrops(NewPartitions(3)...)
+	gerr, ok = errors.Cause(err).(*GuardError)
+	if !ok {
+		t.Fatalf("error invalid error: %v", err)
+	}
+
+	expect = []*Conflict{&Conflict{Reason: "3 partitions exceed max 2"}}
+	if diff := cmp.Diff(gerr.Violations, expect); diff != "" {
+		t.Fatalf("error invalid violations:%s", diff)
+	}
+
+	if gerr.Operation != "COALESCE" {
+		t.Fatalf("error invalid operation %s.", gerr.Operation)
+	}
+}
+
+func Test_boundaryTime(t *testing.T) {
+	type Test struct {
+		Description string
+		Expression  string
+		Time        time.Time
+	}
+
+	tests := []Test{
+		Test{Description: "'2024-03-01'", Expression: "created_at", Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		Test{Description: "'2024-03-01 12:30:00',10", Expression: "created_at,id", Time: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)},
+		Test{Description: "734138", Expression: "to_days(`created_at`)", Time: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Test{Description: "63429523200", Expression: "to_seconds(`created_at`)", Time: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Test{Description: "1262304000", Expression: "unix_timestamp(`created_at`)", Time: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Test{Description: "2010", Expression: "year(`created_at`)", Time: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		bt, ok := boundaryTime(test.Description, test.Expression)
+		if !ok {
+			t.Fatalf("error %s must be time.", test.Description)
+		}
+
+		if !bt.Equal(test.Time) {
+			t.Fatalf("error invalid result. got:%s want:%s", bt, test.Time)
+		}
+	}
+
+	if _, ok := boundaryTime("100", "id"); ok {
+		t.Fatal("error id must not be time.")
+	}
+}
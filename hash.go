@@ -0,0 +1,115 @@
+package partition
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Hash is hash partition part builder
+type Hash struct{}
+
+// Key is key partition part builder
+type Key struct{}
+
+// NewHashPartitioner is partitioner for HASH partitioning.
+// Adds and Drops are mapped to ADD PARTITION PARTITIONS n and COALESCE PARTITION n.
+func NewHashPartitioner(db *sql.DB, table, expresstion string, options ...Option) Partitioner {
+	p := &partitioner{
+		table:         table,
+		db:            db,
+		expression:    expresstion,
+		partitionType: PartitionTypeHash,
+		partBuilder:   &Hash{},
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+	p.resolveTable()
+
+	return p
+}
+
+// NewKeyPartitioner is partitioner for KEY partitioning.
+// columns may be empty to use primary key.
+func NewKeyPartitioner(db *sql.DB, table, columns string, options ...Option) Partitioner {
+	p := &partitioner{
+		table:         table,
+		db:            db,
+		expression:    columns,
+		partitionType: PartitionTypeKey,
+		partBuilder:   &Key{},
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+	p.resolveTable()
+
+	return p
+}
+
+// NewPartitions returns n unnamed partitions.
+// It is used with hash and key partitioner to specify the number of partitions.
+func NewPartitions(n int) []*Partition {
+	partitions := make([]*Partition, 0, n)
+	for i := 0; i < n; i++ {
+		partitions = append(partitions, &Partition{})
+	}
+
+	return partitions
+}
+
+func (h *Hash) buildPart(p *Partition) (string, error) {
+	return buildNamedPart(p)
+}
+
+func (k *Key) buildPart(p *Partition) (string, error) {
+	return buildNamedPart(p)
+}
+
+func buildNamedPart(p *Partition) (string, error) {
+	if p.Name == "" {
+		return "", fmt.Errorf("error no partition name is specified")
+	}
+
+	name, err := quoteName(p.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("PARTITION %s", name) + buildComment(p.Comment), nil
+}
+
+// isNumbered reports whether partitions of the builder can be specified by number
+func isNumbered(b partBuilder) bool {
+	switch b.(type) {
+	case *Hash, *Key:
+		return true
+	}
+
+	return false
+}
+
+// countPartitions returns number of partitions when all partitions are unnamed.
+// named is true when all partitions have name.
+func countPartitions(partitions []*Partition) (count int, named bool, err error) {
+	if len(partitions) == 0 {
+		return 0, false, fmt.Errorf("error no partition is specified")
+	}
+
+	for _, partition := range partitions {
+		if partition.Name != "" {
+			count++
+		}
+	}
+
+	switch count {
+	case 0:
+		return len(partitions), false, nil
+	case len(partitions):
+		return count, true, nil
+	}
+
+	return 0, false, fmt.Errorf("error named and unnamed partitions are mixed")
+}
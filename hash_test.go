@@ -0,0 +1,112 @@
+package partition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHashPartitioner(t *testing.T) {
+	type Test struct {
+		Title  string
+		Input  []*Partition
+		Output string
+		Do     func(...*Partition) (Handler, error)
+	}
+
+	h := NewHashPartitioner(nil, "test", "user_id")
+	k := NewKeyPartitioner(nil, "test", "user_id", Linear(true))
+	tests := []Test{
+		Test{
+			Title:  "create partition",
+			Input:  NewPartitions(16),
+			Output: "ALTER TABLE `test` PARTITION BY HASH (user_id) PARTITIONS 16",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return h.PrepareCreates(partitions...)
+			},
+		},
+		Test{
+			Title:  "create named partition",
+			Input:  []*Partition{NewPartition("p0", "", ""), NewPartition("p1", "", "second")},
+			Output: "ALTER TABLE `test` PARTITION BY HASH (user_id) (PARTITION `p0`, PARTITION `p1` COMMENT = 'second')",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return h.PrepareCreates(partitions...)
+			},
+		},
+		Test{
+			Title:  "add partition",
+			Input:  NewPartitions(2),
+			Output: "ALTER TABLE `test` ADD PARTITION PARTITIONS 2",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return h.PrepareAdds(partitions...)
+			},
+		},
+		Test{
+			Title:  "add named partition",
+			Input:  []*Partition{NewPartition("p16", "", "")},
+			Output: "ALTER TABLE `test` ADD PARTITION (PARTITION `p16`)",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return h.PrepareAdds(partitions...)
+			},
+		},
+		Test{
+			Title:  "drop partition",
+			Input:  NewPartitions(4),
+			Output: "ALTER TABLE `test` COALESCE PARTITION 4",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return h.PrepareDrops(partitions...)
+			},
+		},
+		Test{
+			Title:  "truncate partition",
+			Input:  []*Partition{NewPartition("p0", "", "")},
+			Output: "ALTER TABLE `test` TRUNCATE PARTITION `p0`",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return h.PrepareTruncates(partitions...)
+			},
+		},
+		Test{
+			Title:  "create linear key partition",
+			Input:  NewPartitions(4),
+			Output: "ALTER TABLE `test` PARTITION BY LINEAR KEY (user_id) PARTITIONS 4",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return k.PrepareCreates(partitions...)
+			},
+		},
+		Test{
+			Title:  "drop linear key partition",
+			Input:  NewPartitions(1),
+			Output: "ALTER TABLE `test` COALESCE PARTITION 1",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return k.PrepareDrops(partitions...)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			h, err := test.Do(test.Input...)
+			if err != nil {
+				t.Fatal("error exec.", err.Error())
+			}
+
+			if diff := cmp.Diff(h.Statement(), test.Output); diff != "" {
+				t.Fatalf("error invalid result:%s", diff)
+			}
+		})
+	}
+
+	t.Run("invalid partitions", func(t *testing.T) {
+		if _, err := h.PrepareCreates(); err == nil {
+			t.Fatal("error no partition must be error.")
+		}
+
+		if _, err := h.PrepareAdds(NewPartition("p16", "", ""), &Partition{}); err == nil {
+			t.Fatal("error mixed partitions must be error.")
+		}
+
+		if _, err := k.PrepareDrops(NewPartition("p3", "", "")); err == nil {
+			t.Fatal("error named drop must be error.")
+		}
+	})
+}
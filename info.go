@@ -0,0 +1,152 @@
+package partition
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+)
+
+// Inspector introspects partitions of the table
+type Inspector interface {
+	Partitions() ([]*PartitionInfo, error)
+	PartitionsContext(context.Context) ([]*PartitionInfo, error)
+}
+
+// PartitionInfo describe partition metadata in information_schema.PARTITIONS.
+// TableRows, DataLength and IndexLength are summed up over subpartitions.
+// Engine is available only from SHOW CREATE TABLE.
+type PartitionInfo struct {
+	Name            string
+	OrdinalPosition int
+	Method          string
+	Expression      string
+	Description     string
+	Comment         string
+	Engine          string
+	TableRows       int64
+	DataLength      int64
+	IndexLength     int64
+	CreateTime      time.Time
+	UpdateTime      time.Time
+	Subpartitions   []*SubpartitionInfo
+}
+
+// SubpartitionInfo describe subpartition metadata in information_schema.PARTITIONS
+type SubpartitionInfo struct {
+	Name            string
+	OrdinalPosition int
+	Method          string
+	Expression      string
+	TableRows       int64
+	DataLength      int64
+	IndexLength     int64
+}
+
+// Partition returns partition setting of the info
+func (i *PartitionInfo) Partition() *Partition {
+	partition := NewPartition(i.Name, i.Description, i.Comment)
+	for _, sub := range i.Subpartitions {
+		partition.Subpartitions = append(partition.Subpartitions, NewSubpartition(sub.Name, ""))
+	}
+
+	return partition
+}
+
+// Partitions returns partitions of the table ordered by ordinal position
+func (p *partitioner) Partitions() ([]*PartitionInfo, error) {
+	return p.PartitionsContext(context.Background())
+}
+
+func (p *partitioner) PartitionsContext(ctx context.Context) ([]*PartitionInfo, error) {
+	if p.showCreateTable {
+		return p.partitionsFromShowCreateTable(ctx)
+	}
+
+	dbName, err := p.dbName(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error dbName")
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+SELECT
+  partition_name, partition_ordinal_position, partition_method, partition_expression,
+  partition_description, partition_comment,
+  subpartition_name, subpartition_ordinal_position, subpartition_method, subpartition_expression,
+  table_rows, data_length, index_length, create_time, update_time
+FROM
+  information_schema.PARTITIONS
+WHERE
+  table_name		= ? AND
+  table_schema		= ? AND
+  partition_name IS NOT NULL
+ORDER BY
+  partition_ordinal_position, subpartition_ordinal_position
+`, p.table, dbName)
+	if err != nil {
+		return nil, errors.Wrap(err, "error select partitions")
+	}
+	defer rows.Close()
+
+	infos := []*PartitionInfo{}
+	var last *PartitionInfo
+	for rows.Next() {
+		var (
+			name, method, expression, description, comment sql.NullString
+			subName, subMethod, subExpression              sql.NullString
+			position, subPosition                          sql.NullInt64
+			tableRows, dataLength, indexLength             sql.NullInt64
+			createTime, updateTime                         mysql.NullTime
+		)
+		if err := rows.Scan(
+			&name, &position, &method, &expression,
+			&description, &comment,
+			&subName, &subPosition, &subMethod, &subExpression,
+			&tableRows, &dataLength, &indexLength, &createTime, &updateTime,
+		); err != nil {
+			return nil, errors.Wrap(err, "error scan partition")
+		}
+
+		if last == nil || last.Name != name.String {
+			last = &PartitionInfo{
+				Name:            name.String,
+				OrdinalPosition: int(position.Int64),
+				Method:          method.String,
+				Expression:      expression.String,
+				Description:     description.String,
+				Comment:         comment.String,
+			}
+			infos = append(infos, last)
+		}
+
+		last.TableRows += tableRows.Int64
+		last.DataLength += dataLength.Int64
+		last.IndexLength += indexLength.Int64
+		if createTime.Valid && (last.CreateTime.IsZero() || createTime.Time.Before(last.CreateTime)) {
+			last.CreateTime = createTime.Time
+		}
+		if updateTime.Valid && updateTime.Time.After(last.UpdateTime) {
+			last.UpdateTime = updateTime.Time
+		}
+
+		if subName.Valid {
+			last.Subpartitions = append(last.Subpartitions, &SubpartitionInfo{
+				Name:            subName.String,
+				OrdinalPosition: int(subPosition.Int64),
+				Method:          subMethod.String,
+				Expression:      subExpression.String,
+				TableRows:       tableRows.Int64,
+				DataLength:      dataLength.Int64,
+				IndexLength:     indexLength.Int64,
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows")
+	}
+
+	return infos, nil
+}
@@ -3,7 +3,6 @@ package partition
 import (
 	"database/sql"
 	"fmt"
-	"strings"
 )
 
 // List is list partition part builer
@@ -22,19 +21,30 @@ func NewListPartitioner(db *sql.DB, table, expresstion string, options ...Option
 	for _, option := range options {
 		option(p)
 	}
+	p.resolveTable()
 
 	return p
 }
 
+// NewListColumnsPartitioner is list columns partitioner whose values are tuples of the columns
+func NewListColumnsPartitioner(db *sql.DB, table string, columns []string, options ...Option) Partitioner {
+	options = append([]Option{Type(PartitionTypeList + " COLUMNS")}, options...)
+	return NewListPartitioner(db, table, quoteColumns(columns), options...)
+}
+
 func (l *List) buildPart(p *Partition) (string, error) {
 	if p.Description == "" {
 		return "", fmt.Errorf("error no partition description is spcified")
 	}
 
-	part := fmt.Sprintf("PARTITION %s VALUES IN (%s)", p.Name, p.Description)
-	if p.Comment != "" {
-		part = part + fmt.Sprintf(" COMMENT = '%s'", strings.Replace(p.Comment, "'", "", -1))
+	if err := validateExpression(p.Description); err != nil {
+		return "", err
+	}
+
+	name, err := quoteName(p.Name)
+	if err != nil {
+		return "", err
 	}
 
-	return part, nil
+	return fmt.Sprintf("PARTITION %s VALUES IN (%s)", name, p.Description) + buildComment(p.Comment), nil
 }
@@ -20,7 +20,7 @@ func TestListPartitioner(t *testing.T) {
 		Test{
 			Title:  "create partition sql",
 			Input:  []*Partition{NewPartition("p1", "1", "")},
-			Output: "ALTER TABLE test PARTITION BY LIST (event_id) (PARTITION p1 VALUES IN (1))",
+			Output: "ALTER TABLE `test` PARTITION BY LIST (event_id) (PARTITION `p1` VALUES IN (1))",
 			Do: func(partitions ...*Partition) (Handler, error) {
 				return list.PrepareCreates(partitions...)
 			},
@@ -28,7 +28,7 @@ func TestListPartitioner(t *testing.T) {
 		Test{
 			Title:  "add partition sql",
 			Input:  []*Partition{NewPartition("p2", "2, 3", "")},
-			Output: "ALTER TABLE test ADD PARTITION (PARTITION p2 VALUES IN (2, 3))",
+			Output: "ALTER TABLE `test` ADD PARTITION (PARTITION `p2` VALUES IN (2, 3))",
 			Do: func(partitions ...*Partition) (Handler, error) {
 				return list.PrepareAdds(partitions...)
 			},
@@ -36,7 +36,7 @@ func TestListPartitioner(t *testing.T) {
 		Test{
 			Title:  "drop partition sql",
 			Input:  []*Partition{NewPartition("p1", "", "")},
-			Output: "ALTER TABLE test DROP PARTITION p1",
+			Output: "ALTER TABLE `test` DROP PARTITION `p1`",
 			Do: func(partitions ...*Partition) (Handler, error) {
 				return list.PrepareDrops(partitions...)
 			},
@@ -44,11 +44,19 @@ func TestListPartitioner(t *testing.T) {
 		Test{
 			Title:  "truncate partition sql",
 			Input:  []*Partition{NewPartition("p1", "", "")},
-			Output: "ALTER TABLE test TRUNCATE PARTITION p1",
+			Output: "ALTER TABLE `test` TRUNCATE PARTITION `p1`",
 			Do: func(partitions ...*Partition) (Handler, error) {
 				return list.PrepareTruncates(partitions...)
 			},
 		},
+		Test{
+			Title:  "reorganize partition sql",
+			Input:  []*Partition{NewPartition("p2", "2", ""), NewPartition("p3", "3", "")},
+			Output: "ALTER TABLE `test` REORGANIZE PARTITION `p2` INTO (PARTITION `p2` VALUES IN (2), PARTITION `p3` VALUES IN (3))",
+			Do: func(partitions ...*Partition) (Handler, error) {
+				return list.(Reorganizer).PrepareReorganizes([]*Partition{NewPartition("p2", "", "")}, partitions)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -69,7 +77,7 @@ func Test_list_buildPart(t *testing.T) {
 	l := &List{}
 
 	p := NewPartition("p1122", "1122", "test1122")
-	expect := "PARTITION p1122 VALUES IN (1122) COMMENT = 'test1122'"
+	expect := "PARTITION `p1122` VALUES IN (1122) COMMENT = 'test1122'"
 
 	result, err := l.buildPart(p)
 	if err != nil {
@@ -0,0 +1,321 @@
+package partition
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var identifierRegexp *regexp.Regexp
+
+func init() {
+	identifierRegexp = regexp.MustCompile("^(`[^`]+`|[0-9A-Za-z_$]+)$")
+}
+
+// PartitionLocator returns Locator of current partitions of the table
+type PartitionLocator interface {
+	Locator() (*Locator, error)
+	LocatorContext(ctx context.Context) (*Locator, error)
+}
+
+// Locator finds partitions containing values without querying mysql.
+// It works for RANGE and LIST partitioning by columns or by TO_DAYS, TO_SECONDS, UNIX_TIMESTAMP or YEAR of a column.
+// Strings other than dates are matched exactly and are not ordered because their order depends on collation.
+type Locator struct {
+	method     string
+	expression string
+	partitions []*locatedPartition
+}
+
+type locatedPartition struct {
+	name   string
+	bounds []*bound
+	values [][]*bound
+}
+
+// NewLocator returns locator of partitions loaded from information_schema
+func NewLocator(infos []*PartitionInfo) (*Locator, error) {
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("error table is not partitioned")
+	}
+
+	method, expression := infos[0].Method, infos[0].Expression
+	if !strings.HasPrefix(method, PartitionTypeRange) && !strings.HasPrefix(method, PartitionTypeList) {
+		return nil, fmt.Errorf("error locator supports only range and list partition. method:%s", method)
+	}
+
+	if !evaluable(method, expression) {
+		return nil, fmt.Errorf("error expression can't be evaluated locally. expression:%s", expression)
+	}
+
+	l := &Locator{
+		method:     method,
+		expression: expression,
+	}
+
+	for _, info := range infos {
+		partition := &locatedPartition{name: info.Name}
+		if strings.HasPrefix(method, PartitionTypeRange) {
+			partition.bounds = parseBound(info.Description)
+		} else {
+			for _, value := range splitColumns(info.Description) {
+				if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+					value = value[1 : len(value)-1]
+				}
+				partition.values = append(partition.values, parseBound(value))
+			}
+		}
+		l.partitions = append(l.partitions, partition)
+	}
+
+	return l, nil
+}
+
+// evaluable reports whether values of the expression are computed locally
+func evaluable(method, expression string) bool {
+	if strings.HasSuffix(method, " COLUMNS") {
+		return true
+	}
+
+	expression = strings.TrimSpace(expression)
+	if m := timeFunctionRegexp.FindStringSubmatch(expression); m != nil && strings.HasSuffix(expression, ")") {
+		expression = strings.TrimSpace(expression[len(m[0]) : len(expression)-1])
+	}
+
+	return identifierRegexp.MatchString(expression)
+}
+
+// Locator returns locator of current partitions of the table
+func (p *partitioner) Locator() (*Locator, error) {
+	return p.LocatorContext(context.Background())
+}
+
+func (p *partitioner) LocatorContext(ctx context.Context) (*Locator, error) {
+	infos, err := p.PartitionsContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error Partitions")
+	}
+
+	return NewLocator(infos)
+}
+
+// PartitionFor returns name of partition which contains value of the partitioning column.
+// value is int64, string, time.Time or nil for NULL such as partition values. For multiple columns, value is Tuple.
+// ok is false when no partition contains the value, so inserting it fails in mysql.
+func (l *Locator) PartitionFor(value interface{}) (string, bool, error) {
+	bounds, err := l.bounds(value)
+	if err != nil {
+		return "", false, errors.Wrap(err, "error bounds")
+	}
+
+	i, err := l.index(bounds)
+	if err != nil {
+		return "", false, errors.Wrap(err, "error index")
+	}
+
+	if i < 0 {
+		return "", false, nil
+	}
+
+	return l.partitions[i].name, true, nil
+}
+
+// PartitionsBetween returns names of partitions which may contain values between lo and hi inclusive.
+// They are ordered by ordinal position.
+func (l *Locator) PartitionsBetween(lo, hi interface{}) ([]string, error) {
+	loBounds, err := l.bounds(lo)
+	if err != nil {
+		return nil, errors.Wrap(err, "error bounds")
+	}
+
+	hiBounds, err := l.bounds(hi)
+	if err != nil {
+		return nil, errors.Wrap(err, "error bounds")
+	}
+
+	names := []string{}
+	if c, err := compareValues(loBounds, hiBounds); err != nil {
+		return nil, err
+	} else if 0 < c {
+		return names, nil
+	}
+
+	if strings.HasPrefix(l.method, PartitionTypeList) {
+		for _, partition := range l.partitions {
+			for _, value := range partition.values {
+				lc, err := compareValues(loBounds, value)
+				if err != nil {
+					return nil, err
+				}
+				hc, err := compareValues(value, hiBounds)
+				if err != nil {
+					return nil, err
+				}
+				if lc <= 0 && hc <= 0 {
+					names = append(names, partition.name)
+					break
+				}
+			}
+		}
+		return names, nil
+	}
+
+	first, err := l.rangeIndex(loBounds)
+	if err != nil {
+		return nil, err
+	}
+
+	if first < 0 {
+		return names, nil
+	}
+
+	last, err := l.rangeIndex(hiBounds)
+	if err != nil {
+		return nil, err
+	}
+
+	if last < 0 {
+		last = len(l.partitions) - 1
+	}
+
+	for _, partition := range l.partitions[first : last+1] {
+		names = append(names, partition.name)
+	}
+
+	return names, nil
+}
+
+// bounds returns value evaluated by the partitioning expression
+func (l *Locator) bounds(value interface{}) ([]*bound, error) {
+	values := []interface{}{value}
+	if tuple, ok := value.(Tuple); ok {
+		values = tuple
+	}
+
+	if count := columnCount(l.method, l.expression); len(values) != count {
+		return nil, fmt.Errorf("error partitioning needs %d values but %d values are specified", count, len(values))
+	}
+
+	bounds := []*bound{}
+	for _, v := range values {
+		b, err := l.bound(v)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, b)
+	}
+
+	return bounds, nil
+}
+
+func (l *Locator) bound(value interface{}) (*bound, error) {
+	if value == nil {
+		return &bound{kind: boundNull}, nil
+	}
+
+	if !strings.HasSuffix(l.method, " COLUMNS") {
+		if m := timeFunctionRegexp.FindStringSubmatch(l.expression); m != nil {
+			switch v := value.(type) {
+			case time.Time:
+				// UNIX_TIMESTAMP is allowed only for TIMESTAMP column which doesn't depend on time zone
+				if strings.ToUpper(m[1]) == "UNIX_TIMESTAMP" {
+					return parseValue(fmt.Sprintf("%d", v.Unix())), nil
+				}
+			case string:
+				value = Expr(fmt.Sprintf("%s(%s)", strings.ToUpper(m[1]), quoteString(v)))
+			default:
+				return nil, fmt.Errorf("error value of %s needs time.Time or date string but %T is specified", l.expression, value)
+			}
+		}
+	}
+
+	literal, err := buildValue(value, l.method, l.expression)
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildValue")
+	}
+
+	b := parseValue(literal)
+	if b.kind == boundUnknown || b.kind == boundMax {
+		return nil, fmt.Errorf("error value can't be evaluated locally. value:%s", literal)
+	}
+
+	return b, nil
+}
+
+// index returns position of partition which contains bounds or -1
+func (l *Locator) index(bounds []*bound) (int, error) {
+	if strings.HasPrefix(l.method, PartitionTypeRange) {
+		return l.rangeIndex(bounds)
+	}
+
+	key := boundsKey(bounds)
+	for i, partition := range l.partitions {
+		for _, value := range partition.values {
+			if boundsKey(value) == key {
+				return i, nil
+			}
+		}
+	}
+
+	return -1, nil
+}
+
+// rangeIndex returns position of the first partition whose bound is greater than bounds or -1
+func (l *Locator) rangeIndex(bounds []*bound) (int, error) {
+	for i, partition := range l.partitions {
+		c, err := compareValues(bounds, partition.bounds)
+		if err != nil {
+			return -1, errors.Wrapf(err, "error compare with partition %s", partition.name)
+		}
+		if c < 0 {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// compareValues compares values in column order. NULL is less than any other value as mysql does.
+func compareValues(a, b []*bound) (int, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("error number of values mismatch")
+	}
+
+	for i := range a {
+		var c int
+		switch {
+		case a[i].kind == boundNull && b[i].kind == boundNull:
+			c = 0
+		case a[i].kind == boundNull:
+			c = -1
+		case b[i].kind == boundNull:
+			c = 1
+		case a[i].kind == boundString && b[i].kind == boundString && a[i].key() == b[i].key():
+			c = 0
+		default:
+			var ok bool
+			c, ok = compareBound(a[i], b[i])
+			if !ok {
+				return 0, fmt.Errorf("error %s and %s can't be compared locally", a[i].key(), b[i].key())
+			}
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func boundsKey(bounds []*bound) string {
+	keys := []string{}
+	for _, b := range bounds {
+		keys = append(keys, b.key())
+	}
+
+	return strings.Join(keys, ", ")
+}
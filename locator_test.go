@@ -0,0 +1,142 @@
+package partition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLocator(t *testing.T) {
+	type Test struct {
+		Title     string
+		Infos     []*PartitionInfo
+		Value     interface{}
+		Partition string
+		Found     bool
+		Error     bool
+	}
+
+	rangeColumns := []*PartitionInfo{
+		&PartitionInfo{Name: "p202401", Method: "RANGE COLUMNS", Expression: "`created_at`", Description: "'2024-02-01'"},
+		&PartitionInfo{Name: "p202402", Method: "RANGE COLUMNS", Expression: "`created_at`", Description: "'2024-03-01'"},
+		&PartitionInfo{Name: "pmax", Method: "RANGE COLUMNS", Expression: "`created_at`", Description: "MAXVALUE"},
+	}
+	toDays := []*PartitionInfo{
+		&PartitionInfo{Name: "p202401", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "739282"},
+		&PartitionInfo{Name: "p202402", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "739311"},
+	}
+	multiColumns := []*PartitionInfo{
+		&PartitionInfo{Name: "p0", Method: "RANGE COLUMNS", Expression: "`a`,`b`", Description: "10,20"},
+		&PartitionInfo{Name: "p1", Method: "RANGE COLUMNS", Expression: "`a`,`b`", Description: "10,MAXVALUE"},
+	}
+	list := []*PartitionInfo{
+		&PartitionInfo{Name: "p1", Method: "LIST", Expression: "`event_id`", Description: "1,2"},
+		&PartitionInfo{Name: "pnull", Method: "LIST", Expression: "`event_id`", Description: "NULL,3"},
+	}
+	listColumns := []*PartitionInfo{
+		&PartitionInfo{Name: "jp", Method: "LIST COLUMNS", Expression: "`country`,`region`", Description: "('jp','east'),('jp','west')"},
+	}
+
+	tests := []Test{
+		Test{Title: "range columns", Infos: rangeColumns, Value: "2024-02-01", Partition: "p202402", Found: true},
+		Test{Title: "range columns time", Infos: rangeColumns, Value: time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC), Partition: "p202401", Found: true},
+		Test{Title: "range columns maxvalue", Infos: rangeColumns, Value: "2030-01-01", Partition: "pmax", Found: true},
+		Test{Title: "range columns null", Infos: rangeColumns, Value: nil, Partition: "p202401", Found: true},
+		Test{Title: "to days", Infos: toDays, Value: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), Partition: "p202402", Found: true},
+		Test{Title: "to days string", Infos: toDays, Value: "2024-01-15", Partition: "p202401", Found: true},
+		Test{Title: "to days out of range", Infos: toDays, Value: "2024-03-01", Found: false},
+		Test{Title: "to days int", Infos: toDays, Value: 739282, Error: true},
+		Test{Title: "multi columns", Infos: multiColumns, Value: Tuple{10, 20}, Partition: "p1", Found: true},
+		Test{Title: "multi columns less", Infos: multiColumns, Value: Tuple{9, 100}, Partition: "p0", Found: true},
+		Test{Title: "multi columns arity", Infos: multiColumns, Value: 10, Error: true},
+		Test{Title: "list", Infos: list, Value: 2, Partition: "p1", Found: true},
+		Test{Title: "list null", Infos: list, Value: nil, Partition: "pnull", Found: true},
+		Test{Title: "list missing", Infos: list, Value: 4, Found: false},
+		Test{Title: "list columns", Infos: listColumns, Value: Tuple{"jp", "west"}, Partition: "jp", Found: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			l, err := NewLocator(test.Infos)
+			if err != nil {
+				t.Fatal("error new locator.", err.Error())
+			}
+
+			partition, found, err := l.PartitionFor(test.Value)
+			if test.Error {
+				if err == nil {
+					t.Fatal("error must be error.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal("error partition for.", err.Error())
+			}
+
+			if partition != test.Partition || found != test.Found {
+				t.Fatalf("error invalid result. got:%s,%v want:%s,%v", partition, found, test.Partition, test.Found)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		if _, err := NewLocator([]*PartitionInfo{&PartitionInfo{Name: "p0", Method: "HASH", Expression: "`id`"}}); err == nil {
+			t.Fatal("error hash must be error.")
+		}
+
+		if _, err := NewLocator([]*PartitionInfo{&PartitionInfo{Name: "p0", Method: "RANGE", Expression: "`id` DIV 10", Description: "10"}}); err == nil {
+			t.Fatal("error expression must be error.")
+		}
+	})
+}
+
+func TestLocatorPartitionsBetween(t *testing.T) {
+	type Test struct {
+		Title  string
+		Infos  []*PartitionInfo
+		Lo     interface{}
+		Hi     interface{}
+		Output []string
+	}
+
+	rng := []*PartitionInfo{
+		&PartitionInfo{Name: "p0", Method: "RANGE", Expression: "`id`", Description: "100"},
+		&PartitionInfo{Name: "p1", Method: "RANGE", Expression: "`id`", Description: "200"},
+		&PartitionInfo{Name: "p2", Method: "RANGE", Expression: "`id`", Description: "300"},
+	}
+	list := []*PartitionInfo{
+		&PartitionInfo{Name: "p1", Method: "LIST", Expression: "`event_id`", Description: "1,10"},
+		&PartitionInfo{Name: "p2", Method: "LIST", Expression: "`event_id`", Description: "2,3"},
+		&PartitionInfo{Name: "p4", Method: "LIST", Expression: "`event_id`", Description: "4"},
+	}
+
+	tests := []Test{
+		Test{Title: "range", Infos: rng, Lo: 100, Hi: 250, Output: []string{"p1", "p2"}},
+		Test{Title: "range same partition", Infos: rng, Lo: 0, Hi: 99, Output: []string{"p0"}},
+		Test{Title: "range beyond", Infos: rng, Lo: 150, Hi: 1000, Output: []string{"p1", "p2"}},
+		Test{Title: "range out of range", Infos: rng, Lo: 300, Hi: 1000, Output: []string{}},
+		Test{Title: "range reversed", Infos: rng, Lo: 250, Hi: 100, Output: []string{}},
+		Test{Title: "list", Infos: list, Lo: 3, Hi: 10, Output: []string{"p1", "p2", "p4"}},
+		Test{Title: "list none", Infos: list, Lo: 5, Hi: 9, Output: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			l, err := NewLocator(test.Infos)
+			if err != nil {
+				t.Fatal("error new locator.", err.Error())
+			}
+
+			result, err := l.PartitionsBetween(test.Lo, test.Hi)
+			if err != nil {
+				t.Fatal("error partitions between.", err.Error())
+			}
+
+			if diff := cmp.Diff(result, test.Output); diff != "" {
+				t.Fatalf("error invalid result:%s", diff)
+			}
+		})
+	}
+}
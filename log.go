@@ -0,0 +1,82 @@
+package partition
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	eventPlanned  = "statement planned"
+	eventStarted  = "statement started"
+	eventFinished = "statement finished"
+	eventFailed   = "statement failed"
+)
+
+// Logger receives events of executing statements. *slog.Logger satisfies it.
+// Info receives "statement planned", "statement started" and "statement finished" and
+// Error receives "statement failed", with key value pairs of
+// "table", "statement", "dry_run", "duration" and "error".
+// statement is planned when Prepare builds it and is not started in dry-run.
+// events are not sent to default logger, which prints statements when they are executed.
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// WithLogger set logger which receives all events regardless of Verbose.
+// default logger prints statements to stdout when verbose or dry-run.
+func WithLogger(logger Logger) Option {
+	return func(p *partitioner) {
+		p.logger = logger
+	}
+}
+
+// now returns current time of clock which measures duration of statements and is used by guards and rotator
+func (p *partitioner) now() time.Time {
+	if p.clock != nil {
+		return p.clock()
+	}
+
+	return time.Now()
+}
+
+func (p *partitioner) log() Logger {
+	if p.logger != nil {
+		return p.logger
+	}
+
+	return &stdoutLogger{verbose: p.verbose}
+}
+
+// stdoutLogger is default logger
+type stdoutLogger struct {
+	verbose bool
+}
+
+func (l *stdoutLogger) Info(msg string, args ...interface{}) {
+	if msg == eventFinished && l.verbose {
+		fmt.Println("done.")
+	}
+}
+
+func (l *stdoutLogger) Error(msg string, args ...interface{}) {}
+
+// printStatement prints statement to stdout when it is executed in verbose or dry-run without logger
+func (h *handler) printStatement() {
+	p := h.partitioner
+	if p.logger != nil || (!p.verbose && !p.dryrun) {
+		return
+	}
+
+	prefix := ""
+	if p.dryrun {
+		prefix = " (dry-run)"
+	}
+
+	fmt.Printf("Following SQL sttement to be executed%s.\n", prefix)
+	fmt.Println(h.statement)
+}
+
+func (h *handler) logAttrs(args ...interface{}) []interface{} {
+	return append([]interface{}{"table", h.partitioner.qualifiedTable(), "statement", h.statement, "dry_run", h.partitioner.dryrun}, args...)
+}
@@ -0,0 +1,118 @@
+package partition
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+type testLogger struct {
+	events []string
+}
+
+func (l *testLogger) Info(msg string, args ...interface{}) {
+	l.events = append(l.events, strings.TrimSpace(fmt.Sprintln(append([]interface{}{"INFO", msg}, args...)...)))
+}
+
+func (l *testLogger) Error(msg string, args ...interface{}) {
+	l.events = append(l.events, strings.TrimSpace(fmt.Sprintln(append([]interface{}{"ERROR", msg}, args...)...)))
+}
+
+func TestLogger(t *testing.T) {
+	logger := &testLogger{}
+	p := NewListPartitioner(nil, "test", "event_id", Dryrun(true), WithLogger(logger))
+
+	h, err := p.PrepareDrops(NewPartition("p1", "", ""))
+	if err != nil {
+		t.Fatal("error prepare drops.", err.Error())
+	}
+
+	expect := []string{"INFO statement planned table test statement ALTER TABLE `test` DROP PARTITION `p1` dry_run true"}
+	if diff := cmp.Diff(logger.events, expect); diff != "" {
+		t.Fatalf("error invalid events:%s", diff)
+	}
+
+	if err := h.Execute(); err != nil {
+		t.Fatal("error execute.", err.Error())
+	}
+
+	if diff := cmp.Diff(logger.events, expect); diff != "" {
+		t.Fatalf("error invalid events:%s", diff)
+	}
+
+	logger = &testLogger{}
+	p = NewListPartitioner(nil, "test", "event_id", WithLogger(logger))
+
+	// each statement takes a second
+	now := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	p.(*partitioner).clock = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	h, err = p.PrepareTruncates(NewPartition("p1", "", ""))
+	if err != nil {
+		t.Fatal("error prepare truncates.", err.Error())
+	}
+	h.(*handler).query = func(ctx context.Context, statement string) error {
+		return errors.New("connection refused")
+	}
+
+	if err := h.Execute(); err == nil {
+		t.Fatal("error execute must fail.")
+	}
+
+	expect = []string{
+		"INFO statement planned table test statement ALTER TABLE `test` TRUNCATE PARTITION `p1` dry_run false",
+		"INFO statement started table test statement ALTER TABLE `test` TRUNCATE PARTITION `p1` dry_run false",
+		"ERROR statement failed table test statement ALTER TABLE `test` TRUNCATE PARTITION `p1` dry_run false duration 1s error connection refused",
+	}
+	if diff := cmp.Diff(logger.events, expect); diff != "" {
+		t.Fatalf("error invalid events:%s", diff)
+	}
+}
+
+func TestStdoutLogger(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal("error pipe.", err.Error())
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	p := NewListPartitioner(nil, "test", "event_id", Verbose(true))
+	if _, err := p.PrepareTruncates(NewPartition("p1", "", "")); err != nil {
+		t.Fatal("error prepare truncates.", err.Error())
+	}
+
+	h, err := p.PrepareDrops(NewPartition("p1", "", ""))
+	if err != nil {
+		t.Fatal("error prepare drops.", err.Error())
+	}
+
+	// statement is printed when it is executed
+	p.Dryrun(true)
+	if err := h.Execute(); err != nil {
+		t.Fatal("error execute.", err.Error())
+	}
+
+	w.Close()
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal("error read stdout.", err.Error())
+	}
+
+	expect := "Following SQL sttement to be executed (dry-run).\nALTER TABLE `test` DROP PARTITION `p1`\n"
+	if diff := cmp.Diff(string(out), expect); diff != "" {
+		t.Fatalf("error invalid output:%s", diff)
+	}
+}
@@ -0,0 +1,279 @@
+package partition
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	maintenanceAnalyze  = "ANALYZE"
+	maintenanceCheck    = "CHECK"
+	maintenanceOptimize = "OPTIMIZE"
+	maintenanceRepair   = "REPAIR"
+	maintenanceRebuild  = "REBUILD"
+)
+
+// MaintenanceResult is a row of result set of ANALYZE, CHECK, OPTIMIZE and REPAIR PARTITION.
+// Table is reported by mysql as schema.table and Partition is the partition of the statement, which is empty for all partitions.
+// MsgType is status, error, info, note or warning.
+type MaintenanceResult struct {
+	Table     string
+	Partition string
+	Op        string
+	MsgType   string
+	MsgText   string
+}
+
+// MaintenanceHandler is Handler which keeps result set of the statement after executed
+type MaintenanceHandler interface {
+	Handler
+	Results() []*MaintenanceResult
+}
+
+// Maintainer analyzes, checks, optimizes, repairs and rebuilds partitions
+type Maintainer interface {
+	Analyzes(...*Partition) ([]*MaintenanceResult, error)
+	Checks(...*Partition) ([]*MaintenanceResult, error)
+	Optimizes(...*Partition) ([]*MaintenanceResult, error)
+	Repairs(...*Partition) ([]*MaintenanceResult, error)
+	Rebuilds(...*Partition) error
+
+	AnalyzesContext(context.Context, ...*Partition) ([]*MaintenanceResult, error)
+	ChecksContext(context.Context, ...*Partition) ([]*MaintenanceResult, error)
+	OptimizesContext(context.Context, ...*Partition) ([]*MaintenanceResult, error)
+	RepairsContext(context.Context, ...*Partition) ([]*MaintenanceResult, error)
+	RebuildsContext(context.Context, ...*Partition) error
+
+	PrepareAnalyzes(...*Partition) (MaintenanceHandler, error)
+	PrepareChecks(...*Partition) (MaintenanceHandler, error)
+	PrepareOptimizes(...*Partition) (MaintenanceHandler, error)
+	PrepareRepairs(...*Partition) (MaintenanceHandler, error)
+	PrepareRebuilds(...*Partition) (Handler, error)
+
+	PrepareAnalyzesContext(context.Context, ...*Partition) (MaintenanceHandler, error)
+	PrepareChecksContext(context.Context, ...*Partition) (MaintenanceHandler, error)
+	PrepareOptimizesContext(context.Context, ...*Partition) (MaintenanceHandler, error)
+	PrepareRepairsContext(context.Context, ...*Partition) (MaintenanceHandler, error)
+	PrepareRebuildsContext(context.Context, ...*Partition) (Handler, error)
+}
+
+type maintenanceHandler struct {
+	*handler
+	partition string
+	results   []*MaintenanceResult
+}
+
+func newMaintenanceHandler(p *partitioner, statement, partition string) *maintenanceHandler {
+	h := &maintenanceHandler{
+		handler:   p.newHandler(statement, ""),
+		partition: partition,
+	}
+	h.handler.query = h.queryResults
+
+	return h
+}
+
+func (h *maintenanceHandler) queryResults(ctx context.Context, statement string) error {
+	rows, err := h.partitioner.db.QueryContext(ctx, statement)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	results := []*MaintenanceResult{}
+	for rows.Next() {
+		r := &MaintenanceResult{Partition: h.partition}
+		if err := rows.Scan(&r.Table, &r.Op, &r.MsgType, &r.MsgText); err != nil {
+			return errors.Wrap(err, "error scan result")
+		}
+		results = append(results, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "error rows")
+	}
+	h.results = results
+
+	return nil
+}
+
+// Results returns result set of executed statement. it is empty before executed or in dry-run.
+func (h *maintenanceHandler) Results() []*MaintenanceResult {
+	return h.results
+}
+
+// maintenanceHandlers executes statement for each partition because mysql doesn't report partition in result set
+type maintenanceHandlers []*maintenanceHandler
+
+func (hs maintenanceHandlers) handlers() handlers {
+	converted := handlers{}
+	for _, h := range hs {
+		converted = append(converted, h)
+	}
+
+	return converted
+}
+
+func (hs maintenanceHandlers) Execute() error {
+	return hs.ExecuteContext(context.Background())
+}
+
+func (hs maintenanceHandlers) ExecuteContext(ctx context.Context) error {
+	return hs.handlers().ExecuteContext(ctx)
+}
+
+func (hs maintenanceHandlers) Statement() string {
+	return hs.handlers().Statement()
+}
+
+// Results returns result sets of executed statements in order of partitions
+func (hs maintenanceHandlers) Results() []*MaintenanceResult {
+	results := []*MaintenanceResult{}
+	for _, h := range hs {
+		results = append(results, h.Results()...)
+	}
+
+	return results
+}
+
+// buildMaintenanceSQL builds statement for the operation. all partitions are specified when partitions is empty.
+func (p *partitioner) buildMaintenanceSQL(op string, partitions ...*Partition) (string, error) {
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	names := "ALL"
+	if 0 < len(partitions) {
+		names, err = quoteNames(partitions)
+		if err != nil {
+			return "", errors.Wrap(err, "error quoteNames")
+		}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s %s PARTITION %s", table, op, names), nil
+}
+
+func (p *partitioner) prepareMaintenance(op string, partitions ...*Partition) (MaintenanceHandler, error) {
+	if len(partitions) == 0 {
+		stmt, err := p.buildMaintenanceSQL(op)
+		if err != nil {
+			return nil, errors.Wrap(err, "error buildMaintenanceSQL")
+		}
+		return maintenanceHandlers{newMaintenanceHandler(p, stmt, "")}, nil
+	}
+
+	hs := maintenanceHandlers{}
+	for _, partition := range partitions {
+		stmt, err := p.buildMaintenanceSQL(op, partition)
+		if err != nil {
+			return nil, errors.Wrap(err, "error buildMaintenanceSQL")
+		}
+		hs = append(hs, newMaintenanceHandler(p, stmt, partition.Name))
+	}
+
+	return hs, nil
+}
+
+func (p *partitioner) maintain(ctx context.Context, op string, partitions ...*Partition) ([]*MaintenanceResult, error) {
+	h, err := p.prepareMaintenance(op, partitions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error prepareMaintenance")
+	}
+
+	if err := h.ExecuteContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return h.Results(), nil
+}
+
+func (p *partitioner) Analyzes(partitions ...*Partition) ([]*MaintenanceResult, error) {
+	return p.AnalyzesContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) AnalyzesContext(ctx context.Context, partitions ...*Partition) ([]*MaintenanceResult, error) {
+	return p.maintain(ctx, maintenanceAnalyze, partitions...)
+}
+
+func (p *partitioner) Checks(partitions ...*Partition) ([]*MaintenanceResult, error) {
+	return p.ChecksContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) ChecksContext(ctx context.Context, partitions ...*Partition) ([]*MaintenanceResult, error) {
+	return p.maintain(ctx, maintenanceCheck, partitions...)
+}
+
+func (p *partitioner) Optimizes(partitions ...*Partition) ([]*MaintenanceResult, error) {
+	return p.OptimizesContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) OptimizesContext(ctx context.Context, partitions ...*Partition) ([]*MaintenanceResult, error) {
+	return p.maintain(ctx, maintenanceOptimize, partitions...)
+}
+
+func (p *partitioner) Repairs(partitions ...*Partition) ([]*MaintenanceResult, error) {
+	return p.RepairsContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) RepairsContext(ctx context.Context, partitions ...*Partition) ([]*MaintenanceResult, error) {
+	return p.maintain(ctx, maintenanceRepair, partitions...)
+}
+
+func (p *partitioner) Rebuilds(partitions ...*Partition) error {
+	return p.RebuildsContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) RebuildsContext(ctx context.Context, partitions ...*Partition) error {
+	h, err := p.PrepareRebuildsContext(ctx, partitions...)
+	if err != nil {
+		return errors.Wrap(err, "error PrepareRebuilds")
+	}
+	return h.ExecuteContext(ctx)
+}
+
+func (p *partitioner) PrepareAnalyzes(partitions ...*Partition) (MaintenanceHandler, error) {
+	return p.PrepareAnalyzesContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareAnalyzesContext(ctx context.Context, partitions ...*Partition) (MaintenanceHandler, error) {
+	return p.prepareMaintenance(maintenanceAnalyze, partitions...)
+}
+
+func (p *partitioner) PrepareChecks(partitions ...*Partition) (MaintenanceHandler, error) {
+	return p.PrepareChecksContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareChecksContext(ctx context.Context, partitions ...*Partition) (MaintenanceHandler, error) {
+	return p.prepareMaintenance(maintenanceCheck, partitions...)
+}
+
+func (p *partitioner) PrepareOptimizes(partitions ...*Partition) (MaintenanceHandler, error) {
+	return p.PrepareOptimizesContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareOptimizesContext(ctx context.Context, partitions ...*Partition) (MaintenanceHandler, error) {
+	return p.prepareMaintenance(maintenanceOptimize, partitions...)
+}
+
+func (p *partitioner) PrepareRepairs(partitions ...*Partition) (MaintenanceHandler, error) {
+	return p.PrepareRepairsContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareRepairsContext(ctx context.Context, partitions ...*Partition) (MaintenanceHandler, error) {
+	return p.prepareMaintenance(maintenanceRepair, partitions...)
+}
+
+func (p *partitioner) PrepareRebuilds(partitions ...*Partition) (Handler, error) {
+	return p.PrepareRebuildsContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareRebuildsContext(ctx context.Context, partitions ...*Partition) (Handler, error) {
+	stmt, err := p.buildMaintenanceSQL(maintenanceRebuild, partitions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildMaintenanceSQL")
+	}
+	return p.newHandler(stmt, ""), nil
+}
@@ -0,0 +1,68 @@
+package partition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrepareMaintenance(t *testing.T) {
+	p := NewListPartitioner(nil, "events", "event_id").(*partitioner)
+
+	type Test struct {
+		Prepare func(...*Partition) (Handler, error)
+		Expect  string
+	}
+
+	tests := []Test{
+		Test{
+			Prepare: func(partitions ...*Partition) (Handler, error) { return p.PrepareAnalyzes(partitions...) },
+			Expect:  "ALTER TABLE `events` ANALYZE PARTITION `p1`;\nALTER TABLE `events` ANALYZE PARTITION `p2`",
+		},
+		Test{
+			Prepare: func(partitions ...*Partition) (Handler, error) { return p.PrepareChecks(partitions...) },
+			Expect:  "ALTER TABLE `events` CHECK PARTITION `p1`;\nALTER TABLE `events` CHECK PARTITION `p2`",
+		},
+		Test{
+			Prepare: func(partitions ...*Partition) (Handler, error) { return p.PrepareOptimizes(partitions...) },
+			Expect:  "ALTER TABLE `events` OPTIMIZE PARTITION `p1`;\nALTER TABLE `events` OPTIMIZE PARTITION `p2`",
+		},
+		Test{
+			Prepare: func(partitions ...*Partition) (Handler, error) { return p.PrepareRepairs(partitions...) },
+			Expect:  "ALTER TABLE `events` REPAIR PARTITION `p1`;\nALTER TABLE `events` REPAIR PARTITION `p2`",
+		},
+		Test{
+			Prepare: p.PrepareRebuilds,
+			Expect:  "ALTER TABLE `events` REBUILD PARTITION `p1`,`p2`",
+		},
+	}
+
+	for _, test := range tests {
+		h, err := test.Prepare(NewPartition("p1", "", ""), NewPartition("p2", "", ""))
+		if err != nil {
+			t.Fatal("error prepare.", err.Error())
+		}
+
+		if diff := cmp.Diff(h.Statement(), test.Expect); diff != "" {
+			t.Fatalf("error invalid result:%s", diff)
+		}
+	}
+
+	h, err := p.PrepareChecks()
+	if err != nil {
+		t.Fatal("error prepare checks.", err.Error())
+	}
+
+	if diff := cmp.Diff(h.Statement(), "ALTER TABLE `events` CHECK PARTITION ALL"); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	p.Dryrun(true)
+	if err := h.Execute(); err != nil {
+		t.Fatal("error dry-run.", err.Error())
+	}
+
+	if 0 < len(h.Results()) {
+		t.Fatal("error dry-run must have no result.")
+	}
+}
@@ -0,0 +1,311 @@
+package partition
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MigrationFormat is layout of migration files
+type MigrationFormat string
+
+const (
+	// MigrationGolangMigrate writes VERSION_NAME.up.sql and VERSION_NAME.down.sql for golang-migrate
+	MigrationGolangMigrate MigrationFormat = "golang-migrate"
+	// MigrationGoose writes VERSION_NAME.sql which has -- +goose Up and -- +goose Down sections
+	MigrationGoose MigrationFormat = "goose"
+	// MigrationPlain writes VERSION_NAME/up.sql and VERSION_NAME/down.sql
+	MigrationPlain MigrationFormat = "plain"
+)
+
+// ParseMigrationFormat returns migration format of the name
+func ParseMigrationFormat(name string) (MigrationFormat, error) {
+	switch f := MigrationFormat(strings.ToLower(name)); f {
+	case MigrationGolangMigrate, MigrationGoose, MigrationPlain:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("error unknown migration format %s", name)
+}
+
+const migrationVersionLayout = "20060102150405"
+
+var migrationNameRegexp, migrationVersionRegexp *regexp.Regexp
+
+func init() {
+	migrationNameRegexp = regexp.MustCompile("[^0-9a-z]+")
+	migrationVersionRegexp = regexp.MustCompile("^[0-9]{14}")
+}
+
+// Exporter writes statements of handlers as migration files instead of executing them.
+// Versions are increased by a second when they aren't later than versions written before or found in dir.
+// Down migration is written only when all statements have inverse operation.
+// A file may have several statements, which needs multiStatements=true in DSN of go-sql-driver/mysql.
+// Adds are reverted by drops, creates by REMOVE PARTITIONING, reorganizes by reorganizing back
+// and coalesce of hash and key partitions by adding them. Drops and truncates can't be reverted because rows are lost.
+type Exporter struct {
+	dir    string
+	format MigrationFormat
+
+	now  func() time.Time
+	last time.Time
+}
+
+// NewExporter returns exporter which writes migration files into dir
+func NewExporter(dir string, format MigrationFormat) *Exporter {
+	return &Exporter{
+		dir:    dir,
+		format: format,
+		now:    time.Now,
+	}
+}
+
+// migration is implemented by handlers which can be written as migration.
+// down is empty and reversible is false when some of statements can't be reverted.
+type migration interface {
+	migration() (up []string, down []string, reversible bool, err error)
+}
+
+func (h *handler) migration() ([]string, []string, bool, error) {
+	if h.query != nil {
+		return nil, nil, false, fmt.Errorf("error statement returns result set and can't be exported. statement:%s", h.statement)
+	}
+
+	if h.statement == "" {
+		return []string{}, []string{}, true, nil
+	}
+
+	if h.down == "" {
+		return []string{h.statement}, []string{}, false, nil
+	}
+
+	return []string{h.statement}, []string{h.down}, true, nil
+}
+
+func (hs handlers) migration() ([]string, []string, bool, error) {
+	ups, downs := []string{}, []string{}
+	reversible := true
+	for _, h := range hs {
+		up, down, ok, err := migrationOf(h)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		ups = append(ups, up...)
+		// inverse operations are executed in reverse order
+		downs = append(append([]string{}, down...), downs...)
+		reversible = reversible && ok
+	}
+
+	if !reversible {
+		downs = []string{}
+	}
+
+	return ups, downs, reversible, nil
+}
+
+func (plan *Plan) migration() ([]string, []string, bool, error) {
+	return plan.handlers().migration()
+}
+
+func migrationOf(h Handler) ([]string, []string, bool, error) {
+	m, ok := h.(migration)
+	if !ok {
+		return nil, nil, false, fmt.Errorf("error handler %T can't be exported", h)
+	}
+
+	return m.migration()
+}
+
+// Export writes migration files of the handler such as one returned by PrepareAdds, Planner or Rotator.
+// name is used in file names after version of current time. it returns paths of written files.
+// nothing is written when the handler has no statement.
+func (e *Exporter) Export(name string, h Handler) ([]string, error) {
+	up, down, reversible, err := migrationOf(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "error migration")
+	}
+
+	if len(up) == 0 {
+		return []string{}, nil
+	}
+
+	version, err := e.version()
+	if err != nil {
+		return nil, errors.Wrap(err, "error version")
+	}
+
+	base := version.Format(migrationVersionLayout)
+	if name := strings.Trim(migrationNameRegexp.ReplaceAllString(strings.ToLower(name), "_"), "_"); name != "" {
+		base += "_" + name
+	}
+
+	files := []*migrationFile{}
+	switch e.format {
+	case MigrationGolangMigrate:
+		files = append(files, &migrationFile{base + ".up.sql", buildMigration(up)})
+		if reversible {
+			files = append(files, &migrationFile{base + ".down.sql", buildMigration(down)})
+		}
+	case MigrationGoose:
+		buf := &bytes.Buffer{}
+		buf.WriteString("-- +goose Up\n")
+		buf.WriteString(buildMigration(up))
+		buf.WriteString("\n-- +goose Down\n")
+		if reversible {
+			buf.WriteString(buildMigration(down))
+		} else {
+			buf.WriteString("-- irreversible\n")
+		}
+		files = append(files, &migrationFile{base + ".sql", buf.String()})
+	case MigrationPlain:
+		files = append(files, &migrationFile{filepath.Join(base, "up.sql"), buildMigration(up)})
+		if reversible {
+			files = append(files, &migrationFile{filepath.Join(base, "down.sql"), buildMigration(down)})
+		}
+	default:
+		return nil, fmt.Errorf("error unknown migration format %s", e.format)
+	}
+
+	written := []string{}
+	for _, file := range files {
+		path := filepath.Join(e.dir, file.path)
+		if err := writeMigration(path, file.content); err != nil {
+			return nil, errors.Wrapf(err, "error writeMigration. path:%s", path)
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// version returns current time which is later than last version
+func (e *Exporter) version() (time.Time, error) {
+	latest, err := latestMigrationVersion(e.dir)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if latest.After(e.last) {
+		e.last = latest
+	}
+
+	version := e.now().UTC().Truncate(time.Second)
+	if !version.After(e.last) {
+		version = e.last.Add(time.Second)
+	}
+	e.last = version
+
+	return version, nil
+}
+
+// latestMigrationVersion returns latest version of migrations in dir. it is zero when dir doesn't exist.
+func latestMigrationVersion(dir string) (time.Time, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "error read dir")
+	}
+
+	var latest time.Time
+	for _, entry := range entries {
+		v := migrationVersionRegexp.FindString(entry.Name())
+		if v == "" {
+			continue
+		}
+
+		t, err := time.Parse(migrationVersionLayout, v)
+		if err != nil {
+			continue
+		}
+		if t.After(latest) {
+			latest = t
+		}
+	}
+
+	return latest, nil
+}
+
+type migrationFile struct {
+	path    string
+	content string
+}
+
+func buildMigration(statements []string) string {
+	buf := &bytes.Buffer{}
+	for _, statement := range statements {
+		buf.WriteString(statement)
+		buf.WriteString(";\n")
+	}
+
+	return buf.String()
+}
+
+// writeMigration writes new file. existing migration is never overwritten.
+func writeMigration(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "error mkdir")
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return errors.Wrap(err, "error open")
+	}
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return errors.Wrap(err, "error write")
+	}
+
+	return f.Close()
+}
+
+// buildCreatesDownSQL returns statement which reverts partitioning of the table
+func (p *partitioner) buildCreatesDownSQL() (string, error) {
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s REMOVE PARTITIONING", table), nil
+}
+
+// buildAddsDownSQL returns statement which reverts adding partitions by dropping or coalescing them
+func (p *partitioner) buildAddsDownSQL(partitions ...*Partition) (string, error) {
+	if isNumbered(p.partBuilder) {
+		return p.buildDropsSQL(NewPartitions(len(partitions))...)
+	}
+
+	return p.buildDropsSQL(partitions...)
+}
+
+// buildDropsDownSQL returns statement which reverts dropping partitions.
+// only coalesced hash and key partitions can be added back because rows of dropped partitions are lost.
+func (p *partitioner) buildDropsDownSQL(partitions ...*Partition) (string, error) {
+	if !isNumbered(p.partBuilder) {
+		return "", nil
+	}
+
+	return p.buildAddsSQL(NewPartitions(len(partitions))...)
+}
+
+// buildReorganizesDownSQL returns statement which reorganizes partitions back.
+// it is empty when definitions of partitions before reorganizing are unknown.
+func (p *partitioner) buildReorganizesDownSQL(ctx context.Context, from []*Partition, into []*Partition) (string, error) {
+	for _, partition := range from {
+		if partition.Description == "" && len(partition.Values) == 0 {
+			return "", nil
+		}
+	}
+
+	return p.buildReorganizesSQL(ctx, into, from)
+}
@@ -0,0 +1,233 @@
+package partition
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExporter(t *testing.T) {
+	type Test struct {
+		Title  string
+		Name   string
+		Format MigrationFormat
+		Do     func() (Handler, error)
+		Files  map[string]string
+	}
+
+	list := NewListPartitioner(nil, "events", "event_id")
+	rng := NewRangePartitioner(nil, "logs", "id").(*partitioner)
+	hash := NewHashPartitioner(nil, "users", "user_id")
+
+	tests := []Test{
+		Test{
+			Title:  "golang-migrate adds",
+			Name:   "add p2",
+			Format: MigrationGolangMigrate,
+			Do: func() (Handler, error) {
+				return list.PrepareAdds(NewPartition("p2", "2", ""), NewPartition("p3", "3", ""))
+			},
+			Files: map[string]string{
+				"20240102030405_add_p2.up.sql":   "ALTER TABLE `events` ADD PARTITION (PARTITION `p2` VALUES IN (2), PARTITION `p3` VALUES IN (3));\n",
+				"20240102030405_add_p2.down.sql": "ALTER TABLE `events` DROP PARTITION `p2`,`p3`;\n",
+			},
+		},
+		Test{
+			Title:  "golang-migrate drops",
+			Name:   "Drop P1",
+			Format: MigrationGolangMigrate,
+			Do: func() (Handler, error) {
+				return list.PrepareDrops(NewPartition("p1", "", ""))
+			},
+			Files: map[string]string{
+				"20240102030405_drop_p1.up.sql": "ALTER TABLE `events` DROP PARTITION `p1`;\n",
+			},
+		},
+		Test{
+			Title:  "goose creates",
+			Name:   "create",
+			Format: MigrationGoose,
+			Do: func() (Handler, error) {
+				return rng.PrepareCreates(NewPartition("p0", "100", ""))
+			},
+			Files: map[string]string{
+				"20240102030405_create.sql": "-- +goose Up\nALTER TABLE `logs` PARTITION BY RANGE (id) (PARTITION `p0` VALUES LESS THAN (100));\n\n-- +goose Down\nALTER TABLE `logs` REMOVE PARTITIONING;\n",
+			},
+		},
+		Test{
+			Title:  "goose truncates",
+			Name:   "truncate",
+			Format: MigrationGoose,
+			Do: func() (Handler, error) {
+				return rng.PrepareTruncates(NewPartition("p0", "", ""))
+			},
+			Files: map[string]string{
+				"20240102030405_truncate.sql": "-- +goose Up\nALTER TABLE `logs` TRUNCATE PARTITION `p0`;\n\n-- +goose Down\n-- irreversible\n",
+			},
+		},
+		Test{
+			Title:  "plain reorganizes",
+			Name:   "split",
+			Format: MigrationPlain,
+			Do: func() (Handler, error) {
+				return rng.PrepareReorganizes(
+					[]*Partition{NewPartition("p1", "200", "")},
+					[]*Partition{NewPartition("p0", "100", ""), NewPartition("p1", "200", "")},
+				)
+			},
+			Files: map[string]string{
+				"20240102030405_split/up.sql":   "ALTER TABLE `logs` REORGANIZE PARTITION `p1` INTO (PARTITION `p0` VALUES LESS THAN (100), PARTITION `p1` VALUES LESS THAN (200));\n",
+				"20240102030405_split/down.sql": "ALTER TABLE `logs` REORGANIZE PARTITION `p0`,`p1` INTO (PARTITION `p1` VALUES LESS THAN (200));\n",
+			},
+		},
+		Test{
+			Title:  "plain coalesce",
+			Name:   "coalesce",
+			Format: MigrationPlain,
+			Do: func() (Handler, error) {
+				return hash.PrepareDrops(NewPartitions(2)...)
+			},
+			Files: map[string]string{
+				"20240102030405_coalesce/up.sql":   "ALTER TABLE `users` COALESCE PARTITION 2;\n",
+				"20240102030405_coalesce/down.sql": "ALTER TABLE `users` ADD PARTITION PARTITIONS 2;\n",
+			},
+		},
+		Test{
+			Title:  "handlers in reverse order",
+			Name:   "init",
+			Format: MigrationGolangMigrate,
+			Do: func() (Handler, error) {
+				create, err := list.PrepareCreates(NewPartition("p1", "1", ""))
+				if err != nil {
+					return nil, err
+				}
+				add, err := list.PrepareAdds(NewPartition("p2", "2", ""))
+				if err != nil {
+					return nil, err
+				}
+				return handlers{create, add}, nil
+			},
+			Files: map[string]string{
+				"20240102030405_init.up.sql":   "ALTER TABLE `events` PARTITION BY LIST (event_id) (PARTITION `p1` VALUES IN (1));\nALTER TABLE `events` ADD PARTITION (PARTITION `p2` VALUES IN (2));\n",
+				"20240102030405_init.down.sql": "ALTER TABLE `events` DROP PARTITION `p2`;\nALTER TABLE `events` REMOVE PARTITIONING;\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "migration")
+			if err != nil {
+				t.Fatal("error temp dir.", err.Error())
+			}
+			defer os.RemoveAll(dir)
+
+			h, err := test.Do()
+			if err != nil {
+				t.Fatal("error prepare.", err.Error())
+			}
+
+			e := NewExporter(dir, test.Format)
+			e.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+			paths, err := e.Export(test.Name, h)
+			if err != nil {
+				t.Fatal("error export.", err.Error())
+			}
+
+			files := map[string]string{}
+			for _, path := range paths {
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					t.Fatal("error read file.", err.Error())
+				}
+				rel, err := filepath.Rel(dir, path)
+				if err != nil {
+					t.Fatal("error rel.", err.Error())
+				}
+				files[filepath.ToSlash(rel)] = string(content)
+			}
+
+			if diff := cmp.Diff(files, test.Files); diff != "" {
+				t.Fatalf("error invalid files:%s", diff)
+			}
+
+			if err := ioutil.WriteFile(filepath.Join(dir, "20240102030406_other.sql"), []byte{}, 0644); err != nil {
+				t.Fatal("error write file.", err.Error())
+			}
+
+			// version is increased past migrations in dir even if time is not changed
+			paths, err = e.Export(test.Name, h)
+			if err != nil {
+				t.Fatal("error export.", err.Error())
+			}
+
+			rel, err := filepath.Rel(dir, paths[0])
+			if err != nil {
+				t.Fatal("error rel.", err.Error())
+			}
+
+			if !strings.HasPrefix(rel, "20240102030407") {
+				t.Fatalf("error invalid version of %s.", rel)
+			}
+		})
+	}
+
+	t.Run("same time", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "migration")
+		if err != nil {
+			t.Fatal("error temp dir.", err.Error())
+		}
+		defer os.RemoveAll(dir)
+
+		e := NewExporter(filepath.Join(dir, "migrations"), MigrationGoose)
+		e.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+		versions := []string{}
+		for _, table := range []string{"events", "logs"} {
+			h, err := NewListPartitioner(nil, table, "event_id").PrepareAdds(NewPartition("p2", "2", ""))
+			if err != nil {
+				t.Fatal("error prepare adds.", err.Error())
+			}
+
+			paths, err := e.Export("add p2", h)
+			if err != nil {
+				t.Fatal("error export.", err.Error())
+			}
+			versions = append(versions, strings.SplitN(filepath.Base(paths[0]), "_", 2)[0])
+		}
+
+		if diff := cmp.Diff(versions, []string{"20240102030405", "20240102030406"}); diff != "" {
+			t.Fatalf("error invalid versions:%s", diff)
+		}
+	})
+
+	t.Run("result set", func(t *testing.T) {
+		p := NewListPartitioner(nil, "events", "event_id", Archive(os.TempDir(), ArchiveCSV, false))
+		h, err := p.PrepareDrops(NewPartition("p1", "", ""))
+		if err != nil {
+			t.Fatal("error prepare drops.", err.Error())
+		}
+
+		if _, err := NewExporter(os.TempDir(), MigrationPlain).Export("drop", h); err == nil {
+			t.Fatal("error archive must be error.")
+		}
+	})
+}
+
+func TestParseMigrationFormat(t *testing.T) {
+	for _, name := range []string{"golang-migrate", "Goose", "plain"} {
+		if _, err := ParseMigrationFormat(name); err != nil {
+			t.Fatal("error parse migration format.", err.Error())
+		}
+	}
+
+	if _, err := ParseMigrationFormat("flyway"); err == nil {
+		t.Fatal("error unknown format must be error.")
+	}
+}
@@ -1,9 +1,11 @@
 package partition
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql" // for connect mysql
 	"github.com/pkg/errors"
@@ -14,20 +16,46 @@ const (
 	PartitionTypeList = "LIST"
 	// PartitionTypeRange is default name for range partition
 	PartitionTypeRange = "RANGE"
+	// PartitionTypeHash is default name for hash partition
+	PartitionTypeHash = "HASH"
+	// PartitionTypeKey is default name for key partition
+	PartitionTypeKey = "KEY"
 	// CatchAllPartitionValue is max value for range partition
 	CatchAllPartitionValue = "MAXVALUE"
 )
 
-// Partition describe partition setting
+// Partition describe partition setting.
+// Values are typed values of the partition, Description is used as is when Values is empty.
 type Partition struct {
-	Name        string
-	Description string
-	Comment     string
+	Name          string
+	Description   string
+	Values        []interface{}
+	Comment       string
+	Subpartitions []*Subpartition
 }
 
 // NewPartition is XXX
-func NewPartition(name, description, comment string) *Partition {
-	return &Partition{name, description, comment}
+func NewPartition(name, description, comment string, subpartitions ...*Subpartition) *Partition {
+	return &Partition{
+		Name:          name,
+		Description:   description,
+		Comment:       comment,
+		Subpartitions: subpartitions,
+	}
+}
+
+// Subpartition describe explicit subpartition setting
+type Subpartition struct {
+	Name    string
+	Comment string
+}
+
+// NewSubpartition is XXX
+func NewSubpartition(name, comment string) *Subpartition {
+	return &Subpartition{
+		Name:    name,
+		Comment: comment,
+	}
 }
 
 // Partitioner wrapper for handler
@@ -49,9 +77,51 @@ type Partitioner interface {
 	Verbose(bool)
 }
 
+// ContextPartitioner is Partitioner whose methods take context.
+// Partitioners returned by constructors of this package implement it.
+type ContextPartitioner interface {
+	Partitioner
+
+	IsPartitionedContext(context.Context) (bool, error)
+	HasPartitionContext(context.Context, *Partition) (bool, error)
+
+	CreatesContext(context.Context, ...*Partition) error
+	AddsContext(context.Context, ...*Partition) error
+	DropsContext(context.Context, ...*Partition) error
+	TruncatesContext(context.Context, ...*Partition) error
+
+	PrepareCreatesContext(context.Context, ...*Partition) (Handler, error)
+	PrepareAddsContext(context.Context, ...*Partition) (Handler, error)
+	PrepareDropsContext(context.Context, ...*Partition) (Handler, error)
+	PrepareTruncatesContext(context.Context, ...*Partition) (Handler, error)
+}
+
+// Reorganizer reorganizes partitions of range and list partitioners
+type Reorganizer interface {
+	Reorganizes(from []*Partition, into []*Partition) error
+	ReorganizesContext(ctx context.Context, from []*Partition, into []*Partition) error
+
+	PrepareReorganizes(from []*Partition, into []*Partition) (Handler, error)
+	PrepareReorganizesContext(ctx context.Context, from []*Partition, into []*Partition) (Handler, error)
+}
+
+// capabilities implemented by partitioners of this package
+var (
+	_ ContextPartitioner = (*partitioner)(nil)
+	_ RangePartitioner   = (*partitioner)(nil)
+	_ Inspector          = (*partitioner)(nil)
+	_ Reorganizer        = (*partitioner)(nil)
+	_ Repartitioner      = (*partitioner)(nil)
+	_ Maintainer         = (*partitioner)(nil)
+	_ Exchanger          = (*partitioner)(nil)
+	_ PartitionLocator   = (*partitioner)(nil)
+	_ Explainer          = (*partitioner)(nil)
+)
+
 // Handler exec queries
 type Handler interface {
 	Execute() error
+	ExecuteContext(context.Context) error
 	Statement() string
 }
 
@@ -60,76 +130,159 @@ type partBuilder interface {
 }
 
 type partitioner struct {
+	schema        string
 	table         string
 	db            *sql.DB
 	partitionType string
 	expression    string
 	partBuilder   partBuilder
 
+	subpartitionType       string
+	subpartitionExpression string
+	subpartitionCount      int
+
 	dryrun  bool
 	verbose bool
+	logger  Logger
+	clock   func() time.Time
+	archive *archive
+	guard   *guard
+
+	showCreateTable bool
 
 	// lazy load
 	_partitions []string
 	_dbName     string
 }
 
-func (p *partitioner) dbName() (string, error) {
+// dbName returns schema of the table. default schema of the connection is used when schema is not specified.
+func (p *partitioner) dbName(ctx context.Context) (string, error) {
+	if p.schema != "" {
+		return p.schema, nil
+	}
+
 	if p._dbName != "" {
 		return p._dbName, nil
 	}
 
-	if err := p.db.QueryRow("SELECT DATABASE()").Scan(&p._dbName); err != nil {
+	var name sql.NullString
+	if err := p.db.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&name); err != nil {
 		return "", errors.Wrap(err, "error scan database name")
 	}
 
+	if name.String == "" {
+		return "", fmt.Errorf("error no schema is selected for table %s. specify schema.table or Schema option", p.table)
+	}
+	p._dbName = name.String
+
 	return p._dbName, nil
 }
 
-func (p *partitioner) retrievePartitions() ([]string, error) {
-	dbName, err := p.dbName()
+// resolveTable splits schema.table given to constructor. schema of the table precedes Schema option.
+func (p *partitioner) resolveTable() {
+	if schema, table := splitTable(p.table); schema != "" {
+		p.schema = schema
+		p.table = table
+	}
+
+	if r, ok := p.partBuilder.(*Range); ok {
+		r.table = p.qualifiedTable()
+	}
+}
+
+// qualifiedTable returns schema.table when schema is specified
+func (p *partitioner) qualifiedTable() string {
+	if p.schema == "" {
+		return p.table
+	}
+
+	return p.schema + "." + p.table
+}
+
+func (p *partitioner) retrievePartitions(ctx context.Context) ([]string, error) {
+	infos, err := p.PartitionsContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error Partitions")
+	}
+
+	partitions := []string{}
+	for _, info := range infos {
+		if info.Method == p.partitionType {
+			partitions = append(partitions, info.Name)
+		}
+	}
+
+	return partitions, nil
+}
+
+func (p *partitioner) retrieveSubpartitions(ctx context.Context, partition string) ([]string, error) {
+	if p.showCreateTable {
+		infos, err := p.PartitionsContext(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error Partitions")
+		}
+
+		subpartitions := []string{}
+		for _, info := range infos {
+			if info.Name != partition {
+				continue
+			}
+			for _, sub := range info.Subpartitions {
+				subpartitions = append(subpartitions, sub.Name)
+			}
+		}
+
+		return subpartitions, nil
+	}
+
+	dbName, err := p.dbName(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "error dbName")
 	}
 
-	stmt, err := p.db.Prepare(`
+	stmt, err := p.db.PrepareContext(ctx, `
 SELECT
-  partition_name
+  subpartition_name
 FROM
   information_schema.PARTITIONS
 WHERE
   table_name		= ? AND
   table_schema		= ? AND
-  partition_method	= ?
+  partition_name	= ? AND
+  subpartition_name IS NOT NULL
 ORDER BY
-  partition_name
+  subpartition_ordinal_position
 `)
 	if err != nil {
 		return nil, errors.Wrap(err, "error prepare query")
 	}
 
-	rows, err := stmt.Query(p.table, dbName, p.partitionType)
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, p.table, dbName, partition)
 	if err != nil {
-		return nil, errors.Wrap(err, "error select partitions")
+		return nil, errors.Wrap(err, "error select subpartitions")
 	}
+	defer rows.Close()
 
-	partitions := []string{}
+	subpartitions := []string{}
 	for rows.Next() {
-		var part string
-		if err := rows.Scan(&part); err != nil {
-			if err != sql.ErrNoRows {
-				return nil, errors.Wrap(err, "error scan partition")
-			}
-			return partitions, nil
+		var sub string
+		if err := rows.Scan(&sub); err != nil {
+			return nil, errors.Wrap(err, "error scan subpartition")
 		}
-		partitions = append(partitions, part)
+		subpartitions = append(subpartitions, sub)
 	}
 
-	return partitions, nil
+	return subpartitions, rows.Err()
 }
 
 func (p *partitioner) IsPartitioned() (bool, error) {
-	parts, err := p.retrievePartitions()
+	return p.IsPartitionedContext(context.Background())
+}
+
+func (p *partitioner) IsPartitionedContext(ctx context.Context) (bool, error) {
+	parts, err := p.retrievePartitions(ctx)
 	if err != nil {
 		return false, errors.Wrap(err, "error retrievePartitons")
 	}
@@ -142,20 +295,48 @@ func (p *partitioner) IsPartitioned() (bool, error) {
 }
 
 func (p *partitioner) HasPartition(partition *Partition) (bool, error) {
-	parts, err := p.retrievePartitions()
+	return p.HasPartitionContext(context.Background(), partition)
+}
+
+func (p *partitioner) HasPartitionContext(ctx context.Context, partition *Partition) (bool, error) {
+	parts, err := p.retrievePartitions(ctx)
 	if err != nil {
 		return false, errors.Wrap(err, "error retrieveParitions")
 	}
 
 	for _, part := range parts {
 		if part == partition.Name {
-			return true, nil
+			return p.hasSubpartitions(ctx, partition)
 		}
 	}
 
 	return false, nil
 }
 
+func (p *partitioner) hasSubpartitions(ctx context.Context, partition *Partition) (bool, error) {
+	if len(partition.Subpartitions) == 0 {
+		return true, nil
+	}
+
+	subs, err := p.retrieveSubpartitions(ctx, partition.Name)
+	if err != nil {
+		return false, errors.Wrap(err, "error retrieveSubpartitions")
+	}
+
+	exists := map[string]bool{}
+	for _, sub := range subs {
+		exists[sub] = true
+	}
+
+	for _, sub := range partition.Subpartitions {
+		if !exists[sub.Name] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (p *partitioner) buildParts(partitions ...*Partition) (string, error) {
 	parts := []string{}
 	for _, partition := range partitions {
@@ -163,15 +344,145 @@ func (p *partitioner) buildParts(partitions ...*Partition) (string, error) {
 		if err != nil {
 			return "", errors.Wrapf(err, "error buildPart. name:%s descriptions:%s", partition.Name, partition.Description)
 		}
+
+		if 0 < len(partition.Subpartitions) {
+			subs, err := buildSubparts(partition.Subpartitions)
+			if err != nil {
+				return "", errors.Wrapf(err, "error buildSubparts. name:%s", partition.Name)
+			}
+			part = part + fmt.Sprintf(" (%s)", subs)
+		}
+
 		parts = append(parts, part)
 	}
 
 	return strings.Join(parts, ", "), nil
 }
 
+func buildSubparts(subpartitions []*Subpartition) (string, error) {
+	subs := []string{}
+	for _, sub := range subpartitions {
+		if sub.Name == "" {
+			return "", fmt.Errorf("error no subpartition name is specified")
+		}
+
+		name, err := quoteName(sub.Name)
+		if err != nil {
+			return "", err
+		}
+		subs = append(subs, fmt.Sprintf("SUBPARTITION %s", name)+buildComment(sub.Comment))
+	}
+
+	return strings.Join(subs, ", "), nil
+}
+
+func (p *partitioner) buildSubpartitionClause(partitions ...*Partition) (string, error) {
+	explicit := -1
+	for _, partition := range partitions {
+		n := len(partition.Subpartitions)
+		if explicit != -1 && explicit != n {
+			return "", fmt.Errorf("error all partitions must have same number of subpartitions")
+		}
+		explicit = n
+	}
+
+	if p.subpartitionType == "" {
+		if 0 < explicit {
+			return "", fmt.Errorf("error subpartition is not configured")
+		}
+		return "", nil
+	}
+
+	if !methodRegexp.MatchString(p.subpartitionType) {
+		return "", fmt.Errorf("error invalid subpartition method %s", p.subpartitionType)
+	}
+
+	if err := validateExpression(p.subpartitionExpression); err != nil {
+		return "", errors.Wrap(err, "error validateExpression")
+	}
+
+	clause := fmt.Sprintf(" SUBPARTITION BY %s (%s)", p.subpartitionType, p.subpartitionExpression)
+	if 0 < p.subpartitionCount && explicit <= 0 {
+		clause = clause + fmt.Sprintf(" SUBPARTITIONS %d", p.subpartitionCount)
+	}
+
+	return clause, nil
+}
+
+// definition returns validated partition method and expression like RANGE (TO_DAYS(created_at))
+func (p *partitioner) definition() (string, error) {
+	if !methodRegexp.MatchString(p.partitionType) {
+		return "", fmt.Errorf("error invalid partition type %s", p.partitionType)
+	}
+
+	if err := validateExpression(p.expression); err != nil {
+		return "", errors.Wrap(err, "error validateExpression")
+	}
+
+	return fmt.Sprintf("%s (%s)", p.partitionType, p.expression), nil
+}
+
+// quoteNames validates and quotes partition names
+func quoteNames(partitions []*Partition) (string, error) {
+	if len(partitions) == 0 {
+		return "", fmt.Errorf("error no partition is specified")
+	}
+
+	names := []string{}
+	for _, partition := range partitions {
+		if partition.Name == "" {
+			return "", fmt.Errorf("error no partition name is specified")
+		}
+
+		name, err := quoteName(partition.Name)
+		if err != nil {
+			return "", err
+		}
+		names = append(names, name)
+	}
+
+	return strings.Join(names, ","), nil
+}
+
 func (p *partitioner) buildCreatesSQL(partitions ...*Partition) (string, error) {
+	partitions, err := p.resolvePartitions(partitions)
+	if err != nil {
+		return "", errors.Wrap(err, "error resolvePartitions")
+	}
+
 	if r, ok := p.partBuilder.(*Range); ok && r.catchAllPartitionName != "" {
-		partitions = append(partitions, &Partition{Name: r.catchAllPartitionName, Description: CatchAllPartitionValue})
+		catchAll := p.catchAllPartition(r.catchAllPartitionName)
+		// explicit subpartitions must be defined for all partitions
+		if 0 < len(partitions) {
+			catchAll.Subpartitions = catchAllSubpartitions(catchAll.Name, len(partitions[0].Subpartitions))
+		}
+		partitions = append(partitions, catchAll)
+	}
+
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	definition, err := p.definition()
+	if err != nil {
+		return "", errors.Wrap(err, "error definition")
+	}
+
+	if isNumbered(p.partBuilder) {
+		count, named, err := countPartitions(partitions)
+		if err != nil {
+			return "", errors.Wrap(err, "error countPartitions")
+		}
+
+		if !named {
+			return fmt.Sprintf("ALTER TABLE %s PARTITION BY %s PARTITIONS %d", table, definition, count), nil
+		}
+	}
+
+	sub, err := p.buildSubpartitionClause(partitions...)
+	if err != nil {
+		return "", errors.Wrap(err, "error buildSubpartitionClause")
 	}
 
 	parts, err := p.buildParts(partitions...)
@@ -179,110 +490,408 @@ func (p *partitioner) buildCreatesSQL(partitions ...*Partition) (string, error)
 		return "", errors.Wrap(err, "error buildParts")
 	}
 
-	return fmt.Sprintf("ALTER TABLE %s PARTITION BY %s (%s) (%s)", p.table, p.partitionType, p.expression, parts), nil
+	return fmt.Sprintf("ALTER TABLE %s PARTITION BY %s%s (%s)", table, definition, sub, parts), nil
 }
 
 func (p *partitioner) buildAddsSQL(partitions ...*Partition) (string, error) {
+	partitions, err := p.resolvePartitions(partitions)
+	if err != nil {
+		return "", errors.Wrap(err, "error resolvePartitions")
+	}
+
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	if isNumbered(p.partBuilder) {
+		count, named, err := countPartitions(partitions)
+		if err != nil {
+			return "", errors.Wrap(err, "error countPartitions")
+		}
+
+		if !named {
+			return fmt.Sprintf("ALTER TABLE %s ADD PARTITION PARTITIONS %d", table, count), nil
+		}
+	}
+
 	parts, err := p.buildParts(partitions...)
 	if err != nil {
 		return "", errors.Wrap(err, "error buildParts")
 	}
 
-	return fmt.Sprintf("ALTER TABLE %s ADD PARTITION (%s)", p.table, parts), nil
+	return fmt.Sprintf("ALTER TABLE %s ADD PARTITION (%s)", table, parts), nil
 }
 
 func (p *partitioner) buildDropsSQL(partitions ...*Partition) (string, error) {
-	names := []string{}
-	for _, partition := range partitions {
-		names = append(names, partition.Name)
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	// hash and key partitions can't be dropped by name, only be coalesced by number
+	if isNumbered(p.partBuilder) {
+		count, named, err := countPartitions(partitions)
+		if err != nil {
+			return "", errors.Wrap(err, "error countPartitions")
+		}
+
+		if named {
+			return "", fmt.Errorf("error %s partitions can't be dropped by name. specify number of partitions to coalesce by NewPartitions", p.partitionType)
+		}
+
+		return fmt.Sprintf("ALTER TABLE %s COALESCE PARTITION %d", table, count), nil
 	}
 
-	return fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s", p.table, strings.Join(names, ",")), nil
+	names, err := quoteNames(partitions)
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteNames")
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s", table, names), nil
 }
 
 func (p *partitioner) buildTruncatesSQL(partitions ...*Partition) (string, error) {
-	names := []string{}
-	for _, partition := range partitions {
-		names = append(names, partition.Name)
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	names, err := quoteNames(partitions)
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteNames")
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s TRUNCATE PARTITION %s", table, names), nil
+}
+
+func (p *partitioner) buildReorganizesSQL(ctx context.Context, from []*Partition, into []*Partition) (string, error) {
+	if isNumbered(p.partBuilder) {
+		return "", fmt.Errorf("error reorganize is not supported for %s partition", p.partitionType)
+	}
+
+	if len(into) == 0 {
+		return "", fmt.Errorf("error no partition is specified")
+	}
+
+	from, err := p.resolvePartitions(from)
+	if err != nil {
+		return "", errors.Wrap(err, "error resolvePartitions")
+	}
+
+	into, err = p.resolvePartitions(into)
+	if err != nil {
+		return "", errors.Wrap(err, "error resolvePartitions")
+	}
+
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteTable")
+	}
+
+	names, err := quoteNames(from)
+	if err != nil {
+		return "", errors.Wrap(err, "error quoteNames")
+	}
+
+	parts, err := p.buildParts(into...)
+	if err != nil {
+		return "", errors.Wrap(err, "error buildParts")
+	}
+
+	if r, ok := p.partBuilder.(*Range); ok {
+		if err := p.validateRangeCoverage(ctx, r, from[len(from)-1], into[len(into)-1]); err != nil {
+			return "", errors.Wrap(err, "error validateRangeCoverage")
+		}
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION %s INTO (%s)", table, names, parts), nil
+}
+
+// validateRangeCoverage checks upper bound of reorganized partitions is not changed.
+// lower bound is always same because it is decided by previous partition.
+func (p *partitioner) validateRangeCoverage(ctx context.Context, r *Range, from, into *Partition) error {
+	var fromValue string
+	if from.Description == "" {
+		desc, err := p.retrieveDescription(ctx, from.Name)
+		if err != nil {
+			return errors.Wrap(err, "error retrieveDescription")
+		}
+		fromValue = desc
+	} else {
+		value, err := r.partitionValue(from)
+		if err != nil {
+			return errors.Wrap(err, "error value")
+		}
+		fromValue = value
+	}
+
+	intoValue, err := r.partitionValue(into)
+	if err != nil {
+		return errors.Wrap(err, "error value")
+	}
+
+	if fromValue == intoValue {
+		return nil
+	}
+
+	// TO_DAYS('2010-01-01') is compared with 734138 stored in information_schema
+	c, ok := compareBounds(parseBound(fromValue), parseBound(intoValue))
+	if !ok {
+		return fmt.Errorf("error reorganized range can't be compared. from:%s into:%s", fromValue, intoValue)
+	}
+
+	if c != 0 {
+		return fmt.Errorf("error reorganized range is changed. from:%s into:%s", fromValue, intoValue)
+	}
+
+	return nil
+}
+
+func (p *partitioner) retrieveDescription(ctx context.Context, partition string) (string, error) {
+	if p.db == nil {
+		return "", fmt.Errorf("error description of partition %s is unknown", partition)
+	}
+
+	if p.showCreateTable {
+		infos, err := p.PartitionsContext(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "error Partitions")
+		}
+
+		for _, info := range infos {
+			if info.Name == partition {
+				return info.Description, nil
+			}
+		}
+
+		return "", fmt.Errorf("error partition %s does not exist", partition)
+	}
+
+	dbName, err := p.dbName(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "error dbName")
 	}
 
-	return fmt.Sprintf("ALTER TABLE %s TRUNCATE PARTITION %s", p.table, strings.Join(names, ",")), nil
+	var desc sql.NullString
+	if err := p.db.QueryRowContext(ctx, `
+SELECT DISTINCT
+  partition_description
+FROM
+  information_schema.PARTITIONS
+WHERE
+  table_name		= ? AND
+  table_schema		= ? AND
+  partition_name	= ?
+`, p.table, dbName, partition).Scan(&desc); err != nil {
+		return "", errors.Wrapf(err, "error select partition description. name:%s", partition)
+	}
+
+	return desc.String, nil
 }
 
 func (p *partitioner) Creates(partitions ...*Partition) error {
-	h, err := p.PrepareCreates(partitions...)
+	return p.CreatesContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) CreatesContext(ctx context.Context, partitions ...*Partition) error {
+	h, err := p.PrepareCreatesContext(ctx, partitions...)
 	if err != nil {
 		return errors.Wrap(err, "error PrepareCreates")
 	}
-	return h.Execute()
+	return h.ExecuteContext(ctx)
 }
 
 func (p *partitioner) Adds(partitions ...*Partition) error {
-	h, err := p.PrepareAdds(partitions...)
+	return p.AddsContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) AddsContext(ctx context.Context, partitions ...*Partition) error {
+	h, err := p.PrepareAddsContext(ctx, partitions...)
 	if err != nil {
 		return errors.Wrap(err, "error PrepareAdds")
 	}
-	return h.Execute()
+	return h.ExecuteContext(ctx)
 }
 
 func (p *partitioner) Drops(partitions ...*Partition) error {
-	h, err := p.PrepareDrops(partitions...)
+	return p.DropsContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) DropsContext(ctx context.Context, partitions ...*Partition) error {
+	h, err := p.PrepareDropsContext(ctx, partitions...)
 	if err != nil {
 		return errors.Wrap(err, "error PrepareDrops")
 	}
-	return h.Execute()
+	return h.ExecuteContext(ctx)
 }
 
 func (p *partitioner) Truncates(partitions ...*Partition) error {
-	h, err := p.PrepareTruncates(partitions...)
+	return p.TruncatesContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) TruncatesContext(ctx context.Context, partitions ...*Partition) error {
+	h, err := p.PrepareTruncatesContext(ctx, partitions...)
 	if err != nil {
-		return errors.Wrap(err, "error PrepareAdds")
+		return errors.Wrap(err, "error PrepareTruncates")
 	}
-	return h.Execute()
+	return h.ExecuteContext(ctx)
+}
+
+func (p *partitioner) Reorganizes(from []*Partition, into []*Partition) error {
+	return p.ReorganizesContext(context.Background(), from, into)
+}
+
+func (p *partitioner) ReorganizesContext(ctx context.Context, from []*Partition, into []*Partition) error {
+	h, err := p.PrepareReorganizesContext(ctx, from, into)
+	if err != nil {
+		return errors.Wrap(err, "error PrepareReorganizes")
+	}
+	return h.ExecuteContext(ctx)
 }
 
 func (p *partitioner) PrepareCreates(partitions ...*Partition) (Handler, error) {
+	return p.PrepareCreatesContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareCreatesContext(ctx context.Context, partitions ...*Partition) (Handler, error) {
+	if err := p.validate(ctx, ActionCreate, nil, nil, partitions); err != nil {
+		return nil, errors.Wrap(err, "error validate")
+	}
+
 	stmt, err := p.buildCreatesSQL(partitions...)
 	if err != nil {
 		return nil, errors.Wrap(err, "error buildCreateSQL")
 	}
-	return &handler{
-		statement:   stmt,
-		partitioner: p,
-	}, nil
+
+	down, err := p.buildCreatesDownSQL()
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildCreatesDownSQL")
+	}
+
+	return p.newHandler(stmt, down), nil
 }
 
 func (p *partitioner) PrepareAdds(partitions ...*Partition) (Handler, error) {
+	return p.PrepareAddsContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareAddsContext(ctx context.Context, partitions ...*Partition) (Handler, error) {
+	return p.prepareAdds(ctx, nil, partitions...)
+}
+
+// prepareAdds prepares to add partitions after the dropped partitions are dropped by preceding statement
+func (p *partitioner) prepareAdds(ctx context.Context, dropped []*Partition, partitions ...*Partition) (Handler, error) {
+	if err := p.validate(ctx, ActionAdd, dropped, nil, partitions); err != nil {
+		return nil, errors.Wrap(err, "error validate")
+	}
+
+	// partitions can't be added after catch all partition
+	if _, ok := p.partBuilder.(*Range); ok && p.db != nil {
+		name, err := p.retrieveCatchAllPartition(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error retrieveCatchAllPartition")
+		}
+		if name != "" {
+			return p.prepareReorganizeCatchAll(ctx, dropped, name, partitions...)
+		}
+	}
+
 	stmt, err := p.buildAddsSQL(partitions...)
 	if err != nil {
 		return nil, errors.Wrap(err, "error buildAddsSQL")
 	}
-	return &handler{
-		statement:   stmt,
-		partitioner: p,
-	}, nil
+
+	down, err := p.buildAddsDownSQL(partitions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildAddsDownSQL")
+	}
+
+	return p.newHandler(stmt, down), nil
 }
 
 func (p *partitioner) PrepareDrops(partitions ...*Partition) (Handler, error) {
+	return p.PrepareDropsContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareDropsContext(ctx context.Context, partitions ...*Partition) (Handler, error) {
+	operation := "DROP"
+	if isNumbered(p.partBuilder) {
+		operation = "COALESCE"
+	}
+
+	if err := p.checkGuard(ctx, operation, partitions); err != nil {
+		return nil, errors.Wrap(err, "error checkGuard")
+	}
+
 	stmt, err := p.buildDropsSQL(partitions...)
 	if err != nil {
 		return nil, errors.Wrap(err, "error buildDropsSQL")
 	}
-	return &handler{
-		statement:   stmt,
-		partitioner: p,
-	}, nil
+
+	down, err := p.buildDropsDownSQL(partitions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildDropsDownSQL")
+	}
+
+	h := p.newHandler(stmt, down)
+
+	// partitions are dropped after all of them are archived
+	if p.archive != nil {
+		hs, archived, err := p.prepareArchives(partitions...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error prepareArchives")
+		}
+		h.query = func(ctx context.Context, statement string) error {
+			return p.dropArchived(ctx, archived, statement)
+		}
+		return append(hs, h), nil
+	}
+
+	return h, nil
 }
 
 func (p *partitioner) PrepareTruncates(partitions ...*Partition) (Handler, error) {
+	return p.PrepareTruncatesContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareTruncatesContext(ctx context.Context, partitions ...*Partition) (Handler, error) {
+	if err := p.checkGuard(ctx, "TRUNCATE", partitions); err != nil {
+		return nil, errors.Wrap(err, "error checkGuard")
+	}
+
 	stmt, err := p.buildTruncatesSQL(partitions...)
 	if err != nil {
 		return nil, errors.Wrap(err, "error buildTruncatesSQL")
 	}
-	return &handler{
-		statement:   stmt,
-		partitioner: p,
-	}, nil
+	return p.newHandler(stmt, ""), nil
+}
+
+func (p *partitioner) PrepareReorganizes(from []*Partition, into []*Partition) (Handler, error) {
+	return p.PrepareReorganizesContext(context.Background(), from, into)
+}
+
+func (p *partitioner) PrepareReorganizesContext(ctx context.Context, from []*Partition, into []*Partition) (Handler, error) {
+	return p.prepareReorganizes(ctx, nil, from, into)
+}
+
+// prepareReorganizes prepares to reorganize partitions after the dropped partitions are dropped by preceding statement
+func (p *partitioner) prepareReorganizes(ctx context.Context, dropped []*Partition, from []*Partition, into []*Partition) (Handler, error) {
+	if err := p.validate(ctx, ActionReorganize, dropped, from, into); err != nil {
+		return nil, errors.Wrap(err, "error validate")
+	}
+
+	stmt, err := p.buildReorganizesSQL(ctx, from, into)
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildReorganizesSQL")
+	}
+
+	down, err := p.buildReorganizesDownSQL(ctx, from, into)
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildReorganizesDownSQL")
+	}
+
+	return p.newHandler(stmt, down), nil
 }
 
 func (p *partitioner) Dryrun(dryrun bool) {
@@ -319,6 +928,45 @@ func Type(t string) Option {
 	}
 }
 
+// Linear set linear hash or linear key partitioning.
+// it is effective only for hash and key partitioner.
+func Linear(linear bool) Option {
+	return func(p *partitioner) {
+		if !isNumbered(p.partBuilder) {
+			return
+		}
+
+		t := strings.TrimPrefix(p.partitionType, "LINEAR ")
+		if linear {
+			t = "LINEAR " + t
+		}
+		p.partitionType = t
+	}
+}
+
+// SubpartitionBy set subpartitioning for range and list partitioner.
+// method is HASH, KEY, LINEAR HASH or LINEAR KEY.
+// count is number of subpartitions of each partition. 0 means subpartitions are specified explicitly.
+func SubpartitionBy(method, expression string, count int) Option {
+	return func(p *partitioner) {
+		if isNumbered(p.partBuilder) {
+			return
+		}
+
+		p.subpartitionType = strings.ToUpper(method)
+		p.subpartitionExpression = expression
+		p.subpartitionCount = count
+	}
+}
+
+// Schema set schema of the table instead of default schema of the connection.
+// schema.table given to constructor precedes it.
+func Schema(schema string) Option {
+	return func(p *partitioner) {
+		p.schema = schema
+	}
+}
+
 // CatchAllPartitionName set catch all partition name for range partition
 func CatchAllPartitionName(name string) Option {
 	return func(p *partitioner) {
@@ -332,37 +980,93 @@ type handler struct {
 	statement   string
 	executed    bool
 	partitioner *partitioner
+
+	// down reverts statement in exported migration. it is empty when statement is irreversible.
+	down string
+
+	// query is used instead of exec for statement which returns result set
+	query func(ctx context.Context, statement string) error
 }
 
-func (h *handler) Execute() error {
-	if h.executed {
-		return errors.New("error statement is already execute")
+// newHandler returns handler of the statement and tells logger set by WithLogger the statement is planned.
+// down is empty when the statement is irreversible.
+func (p *partitioner) newHandler(statement, down string) *handler {
+	h := &handler{
+		statement:   statement,
+		partitioner: p,
+		down:        down,
+	}
+	if p.logger != nil {
+		p.logger.Info(eventPlanned, h.logAttrs()...)
 	}
 
-	if h.partitioner.verbose || h.partitioner.dryrun {
-		prefix := ""
-		if h.partitioner.dryrun {
-			prefix = " (dry-run)"
-		}
+	return h
+}
+
+func (h *handler) Execute() error {
+	return h.ExecuteContext(context.Background())
+}
 
-		fmt.Printf("Following SQL sttement to be executed%s.\n", prefix)
-		fmt.Println(h.statement)
+func (h *handler) ExecuteContext(ctx context.Context) error {
+	if h.executed {
+		return errors.New("error statement is already execute")
 	}
+	h.printStatement()
 
 	if !h.partitioner.dryrun {
-		if _, err := h.partitioner.db.Exec(h.statement); err != nil {
+		logger := h.partitioner.log()
+		logger.Info(eventStarted, h.logAttrs()...)
+
+		start := h.partitioner.now()
+		if err := h.run(ctx); err != nil {
+			logger.Error(eventFailed, h.logAttrs("duration", h.partitioner.now().Sub(start), "error", err)...)
 			return errors.Wrap(err, "error exec statement")
 		}
 
-		if h.partitioner.verbose {
-			fmt.Println("done.")
-		}
+		logger.Info(eventFinished, h.logAttrs("duration", h.partitioner.now().Sub(start))...)
 	}
 	h.executed = true
 
 	return nil
 }
 
+func (h *handler) run(ctx context.Context) error {
+	if h.query != nil {
+		return h.query(ctx, h.statement)
+	}
+
+	_, err := h.partitioner.db.ExecContext(ctx, h.statement)
+	return err
+}
+
 func (h *handler) Statement() string {
 	return h.statement
 }
+
+// handlers executes handlers in order as one handler
+type handlers []Handler
+
+func (hs handlers) Execute() error {
+	return hs.ExecuteContext(context.Background())
+}
+
+func (hs handlers) ExecuteContext(ctx context.Context) error {
+	for _, h := range hs {
+		if err := h.ExecuteContext(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (hs handlers) Statement() string {
+	stmts := []string{}
+	for _, h := range hs {
+		if stmt := h.Statement(); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	return strings.Join(stmts, ";\n")
+}
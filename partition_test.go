@@ -1,10 +1,17 @@
 package partition
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/lestrrat/go-test-mysqld"
+	"github.com/pkg/errors"
 )
 
 func TestList(t *testing.T) {
@@ -27,7 +34,7 @@ func TestList(t *testing.T) {
 		t.Fatal("error exec sceham.", err.Error())
 	}
 
-	p := NewListPartitioner(db, "test", "event_id")
+	p := NewListPartitioner(db, "test", "event_id").(*partitioner)
 
 	partitioned, err := p.IsPartitioned()
 	if err != nil {
@@ -164,7 +171,7 @@ func TestRange(t *testing.T) {
 		t.Fatal("error exec sceham.", err.Error())
 	}
 
-	p := NewRangePartitioner(db, "test2", "created_at", Type("range columns"))
+	p := NewRangePartitioner(db, "test2", "created_at", Type("range columns")).(*partitioner)
 
 	result, err := p.IsPartitioned()
 	if err != nil {
@@ -296,6 +303,158 @@ func TestRange(t *testing.T) {
 			t.Fatal("error invalid result.")
 		}
 	})
+
+	t.Run("reorganize partition", func(t *testing.T) {
+		from := []*Partition{NewPartition("p20120101", "", "")}
+		into := []*Partition{
+			NewPartition("p20110101", "2011-01-01", ""),
+			NewPartition("p20120101", "2012-01-01", ""),
+		}
+
+		if err := p.Reorganizes(from, into); err != nil {
+			t.Fatal("error reorganizes.", err.Error())
+		}
+
+		has, err := p.HasPartition(into[0])
+		if err != nil {
+			t.Fatal("error has partition.", err.Error())
+		}
+
+		if !has {
+			t.Fatal("error invalid result.")
+		}
+	})
+}
+
+func TestRangeCatchAll(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test7 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      created_at datetime NOT NULL,
+      PRIMARY KEY (id, created_at)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewRangePartitioner(db, "test7", "created_at", Type("range columns")).(*partitioner)
+
+	if err := p.Creates(NewPartition("p20100101", "2010-01-01", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	has, err := p.HasCatchAllPartition()
+	if err != nil {
+		t.Fatal("error has catch all partition.", err.Error())
+	}
+
+	if has {
+		t.Fatal("error invalid result.")
+	}
+
+	p = NewRangePartitioner(db, "test7", "created_at", Type("range columns"), CatchAllPartitionName("pmax")).(*partitioner)
+	if err := p.AddCatchAllPartition(); err != nil {
+		t.Fatal("error add catch all partition.", err.Error())
+	}
+
+	has, err = p.HasCatchAllPartition()
+	if err != nil {
+		t.Fatal("error has catch all partition.", err.Error())
+	}
+
+	if !has {
+		t.Fatal("error invalid result.")
+	}
+
+	partition := NewPartition("p20110101", "2011-01-01", "")
+	if err := p.Adds(partition); err != nil {
+		t.Fatal("error adds.", err.Error())
+	}
+
+	has, err = p.HasPartition(partition)
+	if err != nil {
+		t.Fatal("error has partition.", err.Error())
+	}
+
+	if !has {
+		t.Fatal("error invalid result.")
+	}
+
+	has, err = p.HasCatchAllPartition()
+	if err != nil {
+		t.Fatal("error has catch all partition.", err.Error())
+	}
+
+	if !has {
+		t.Fatal("error invalid result.")
+	}
+}
+
+func TestPartitions(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test8 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewListPartitioner(db, "test8", "event_id", SubpartitionBy("hash", "id", 2)).(*partitioner)
+
+	if err := p.Creates(NewPartition("p10", "10", "ten"), NewPartition("p2", "2", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	infos, err := p.Partitions()
+	if err != nil {
+		t.Fatal("error partitions.", err.Error())
+	}
+
+	if len(infos) != 2 {
+		t.Fatalf("error invalid result. got:%d want:%d.", len(infos), 2)
+	}
+
+	// ordered by ordinal position, not by name
+	if infos[0].Name != "p10" || infos[0].OrdinalPosition != 1 || infos[0].Description != "10" || infos[0].Comment != "ten" {
+		t.Fatalf("error invalid result. %#v", infos[0])
+	}
+
+	if infos[1].Name != "p2" || infos[1].Method != PartitionTypeList {
+		t.Fatalf("error invalid result. %#v", infos[1])
+	}
+
+	if len(infos[0].Subpartitions) != 2 || infos[0].Subpartitions[0].Method != PartitionTypeHash {
+		t.Fatalf("error invalid subpartitions. %#v", infos[0].Subpartitions)
+	}
+
+	has, err := p.HasPartition(NewPartition("p10", "", "", NewSubpartition(infos[0].Subpartitions[1].Name, "")))
+	if err != nil {
+		t.Fatal("error has partition.", err.Error())
+	}
+
+	if !has {
+		t.Fatal("error invalid result.")
+	}
 }
 
 func TestDryrun(t *testing.T) {
@@ -318,7 +477,7 @@ func TestDryrun(t *testing.T) {
 		t.Fatal("error exec sceham.", err.Error())
 	}
 
-	p := NewListPartitioner(db, "test4", "event_id", Dryrun(true))
+	p := NewListPartitioner(db, "test4", "event_id", Dryrun(true)).(*partitioner)
 
 	result, err := p.IsPartitioned()
 	if err != nil {
@@ -364,7 +523,7 @@ func TestHandler(t *testing.T) {
 		t.Fatal("error exec sceham.", err.Error())
 	}
 
-	p := NewListPartitioner(db, "test5", "event_id")
+	p := NewListPartitioner(db, "test5", "event_id").(*partitioner)
 
 	t.Run("create", func(t *testing.T) {
 		partition := NewPartition("p1", "1", "")
@@ -469,4 +628,662 @@ func TestHandler(t *testing.T) {
 			t.Fatal("error invalid result.")
 		}
 	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		partition := NewPartition("p4", "4", "")
+		if err := p.AddsContext(ctx, partition); err == nil {
+			t.Fatal("error canceled context must be error.")
+		}
+
+		has, err := p.HasPartition(partition)
+		if err != nil {
+			t.Fatal("error has partition.", err.Error())
+		}
+
+		if has {
+			t.Fatal("error invalid result.")
+		}
+	})
+}
+
+func TestHash(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test6 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      user_id INTEGER NOT NULL,
+      PRIMARY KEY (id, user_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewHashPartitioner(db, "test6", "user_id").(*partitioner)
+
+	if err := p.Creates(NewPartitions(4)...); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	has, err := p.HasPartition(NewPartition("p3", "", ""))
+	if err != nil {
+		t.Fatal("error has partition.", err.Error())
+	}
+
+	if !has {
+		t.Fatal("error hasn't partition.")
+	}
+
+	t.Run("add partitions", func(t *testing.T) {
+		if err := p.Adds(NewPartitions(2)...); err != nil {
+			t.Fatal("error adds.", err.Error())
+		}
+
+		has, err := p.HasPartition(NewPartition("p5", "", ""))
+		if err != nil {
+			t.Fatal("error has partition.", err.Error())
+		}
+
+		if !has {
+			t.Fatal("error invalid status.")
+		}
+	})
+
+	t.Run("drop partitions", func(t *testing.T) {
+		if err := p.Drops(NewPartitions(3)...); err != nil {
+			t.Fatal("error drops.", err.Error())
+		}
+
+		has, err := p.HasPartition(NewPartition("p3", "", ""))
+		if err != nil {
+			t.Fatal("error has partition.", err.Error())
+		}
+
+		if has {
+			t.Fatal("error invalid status.")
+		}
+	})
+}
+
+func TestExchange(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test9 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewListPartitioner(db, "test9", "event_id").(*partitioner)
+	if err := p.Creates(NewPartition("p1", "1", ""), NewPartition("p2", "2", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	if _, err := db.Exec("INSERT INTO test9 (event_id) VALUES (1), (1), (2)"); err != nil {
+		t.Fatal("error insert.", err.Error())
+	}
+
+	if err := p.CreateExchangeTable("test9_archive"); err != nil {
+		t.Fatal("error create exchange table.", err.Error())
+	}
+
+	if err := p.ExchangePartition(NewPartition("p1", "", ""), "test9_archive", true); err != nil {
+		t.Fatal("error exchange partition.", err.Error())
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM test9_archive").Scan(&count); err != nil {
+		t.Fatal("error count.", err.Error())
+	}
+
+	if count != 2 {
+		t.Fatalf("error invalid count %d.", count)
+	}
+
+	t.Run("incompatible table", func(t *testing.T) {
+		if _, err := db.Exec("CREATE TABLE test9_other (id BIGINT unsigned NOT NULL, PRIMARY KEY (id))"); err != nil {
+			t.Fatal("error exec sceham.", err.Error())
+		}
+
+		_, err := p.PrepareExchangePartition(NewPartition("p2", "", ""), "test9_other", false)
+		if _, ok := errors.Cause(err).(*ValidationError); !ok {
+			t.Fatalf("error invalid error: %v", err)
+		}
+	})
+}
+
+func TestMaintenance(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test10 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewListPartitioner(db, "test10", "event_id").(*partitioner)
+	if err := p.Creates(NewPartition("p1", "1", ""), NewPartition("p2", "2", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	results, err := p.Checks(NewPartition("p1", "", ""), NewPartition("p2", "", ""))
+	if err != nil {
+		t.Fatal("error checks.", err.Error())
+	}
+
+	if len(results) == 0 || results[len(results)-1].Op != "check" || results[len(results)-1].MsgText != "OK" {
+		t.Fatalf("error invalid results %#v.", results)
+	}
+
+	if results[0].Partition != "p1" || results[len(results)-1].Partition != "p2" {
+		t.Fatalf("error invalid partitions of results %#v.", results)
+	}
+
+	if _, err := p.Analyzes(); err != nil {
+		t.Fatal("error analyzes.", err.Error())
+	}
+
+	if err := p.Rebuilds(NewPartition("p2", "", "")); err != nil {
+		t.Fatal("error rebuilds.", err.Error())
+	}
+}
+
+func TestRepartition(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test11 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	list := NewListPartitioner(db, "test11", "event_id").(*partitioner)
+	if err := list.Creates(NewPartition("p1", "1", ""), NewPartition("p2", "2", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	r := NewRangePartitioner(db, "test11", "event_id", CatchAllPartitionName("pmax")).(*partitioner)
+	partitions := []*Partition{NewRangePartitionInt("p10", 10)}
+	if err := r.Repartition(partitions...); err != nil {
+		t.Fatal("error repartition.", err.Error())
+	}
+
+	h, err := r.PrepareRepartition(partitions...)
+	if err != nil {
+		t.Fatal("error prepare repartition.", err.Error())
+	}
+
+	if h.Statement() != "" {
+		t.Fatalf("error repartition must be idempotent. %s", h.Statement())
+	}
+
+	if err := r.RemovePartitioning(); err != nil {
+		t.Fatal("error remove partitioning.", err.Error())
+	}
+
+	h, err = r.PrepareRemovePartitioning()
+	if err != nil {
+		t.Fatal("error prepare remove partitioning.", err.Error())
+	}
+
+	if h.Statement() != "" {
+		t.Fatalf("error remove partitioning must be idempotent. %s", h.Statement())
+	}
+}
+
+func TestArchive(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test12 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      payload VARBINARY(8),
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal("error temp dir.", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	p := NewListPartitioner(db, "test12", "event_id", Archive(dir, ArchiveJSONLines, true)).(*partitioner)
+	if err := p.Creates(NewPartition("p1", "1", ""), NewPartition("p2", "2", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	if _, err := db.Exec("INSERT INTO test12 (event_id, payload) VALUES (1, X'FF00'), (1, NULL), (2, NULL)"); err != nil {
+		t.Fatal("error insert.", err.Error())
+	}
+
+	if err := p.Drops(NewPartition("p1", "", "")); err != nil {
+		t.Fatal("error drops.", err.Error())
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "test12.p1.manifest.json"))
+	if err != nil {
+		t.Fatal("error read manifest.", err.Error())
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(b, manifest); err != nil {
+		t.Fatal("error unmarshal manifest.", err.Error())
+	}
+
+	if manifest.Rows != 2 || manifest.Description != "1" || manifest.File != "test12.p1.jsonl.gz" || len(manifest.Base64) != 1 || manifest.Base64[0] != "payload" {
+		t.Fatalf("error invalid manifest %#v.", manifest)
+	}
+
+	for _, name := range []string{"test12.p1.manifest.json", "test12.p1.jsonl.gz"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal("error stat.", err.Error())
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("error %s must be readable only by owner. mode:%s", name, info.Mode())
+		}
+	}
+
+	has, err := p.HasPartition(NewPartition("p1", "", ""))
+	if err != nil {
+		t.Fatal("error has partition.", err.Error())
+	}
+
+	if has {
+		t.Fatal("error archived partition must be dropped.")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "test12.p2.manifest.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal("error write manifest.", err.Error())
+	}
+
+	if err := p.Drops(NewPartition("p2", "", "")); err == nil {
+		t.Fatal("error existing archive must not be overwritten.")
+	}
+}
+
+func TestGuardNonEmpty(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test13 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewListPartitioner(db, "test13", "event_id", RefuseNonEmpty()).(*partitioner)
+	if err := p.Creates(NewPartition("p1", "1", ""), NewPartition("p2", "2", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	if _, err := db.Exec("INSERT INTO test13 (event_id) VALUES (1)"); err != nil {
+		t.Fatal("error insert.", err.Error())
+	}
+
+	if _, ok := errors.Cause(p.Truncates(NewPartition("p1", "", ""))).(*GuardError); !ok {
+		t.Fatal("error non empty partition must be refused.")
+	}
+
+	if err := p.Drops(NewPartition("p2", "", "")); err != nil {
+		t.Fatal("error drops.", err.Error())
+	}
+}
+
+func TestSchema(t *testing.T) {
+	type Test struct {
+		Partitioner Partitioner
+		Expect      string
+	}
+
+	tests := []Test{
+		Test{
+			Partitioner: NewListPartitioner(nil, "analytics.events", "event_id"),
+			Expect:      "ALTER TABLE `analytics`.`events` DROP PARTITION `p1`",
+		},
+		Test{
+			Partitioner: NewListPartitioner(nil, "events", "event_id", Schema("analytics")),
+			Expect:      "ALTER TABLE `analytics`.`events` DROP PARTITION `p1`",
+		},
+		Test{
+			Partitioner: NewListPartitioner(nil, "analytics.events", "event_id", Schema("other")),
+			Expect:      "ALTER TABLE `analytics`.`events` DROP PARTITION `p1`",
+		},
+	}
+
+	for _, test := range tests {
+		h, err := test.Partitioner.PrepareDrops(NewPartition("p1", "", ""))
+		if err != nil {
+			t.Fatal("error prepare drops.", err.Error())
+		}
+
+		if h.Statement() != test.Expect {
+			t.Fatalf("error invalid statement. got:%s want:%s", h.Statement(), test.Expect)
+		}
+	}
+
+	h, err := NewHashPartitioner(nil, "events", "id", Schema("analytics")).PrepareDrops(NewPartitions(1)...)
+	if err != nil {
+		t.Fatal("error prepare drops.", err.Error())
+	}
+
+	if expect := "ALTER TABLE `analytics`.`events` COALESCE PARTITION 1"; h.Statement() != expect {
+		t.Fatalf("error invalid statement. got:%s want:%s", h.Statement(), expect)
+	}
+
+	r := NewRangePartitioner(nil, "events", "id", Schema("analytics"), CatchAllPartitionName("pmax")).(*partitioner)
+	h, err = r.PrepareAddCatchAllPartition()
+	if err != nil {
+		t.Fatal("error prepare add catch all partition.", err.Error())
+	}
+
+	if expect := "ALTER TABLE `analytics`.`events` ADD PARTITION (PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"; h.Statement() != expect {
+		t.Fatalf("error invalid statement. got:%s want:%s", h.Statement(), expect)
+	}
+}
+
+func TestSchemaQualified(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec("CREATE DATABASE analytics"); err != nil {
+		t.Fatal("error create database.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE analytics.events (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	if _, err := NewListPartitioner(db, "events", "event_id").IsPartitioned(); err == nil {
+		t.Fatal("error no schema must be error.")
+	}
+
+	p := NewListPartitioner(db, "analytics.events", "event_id").(*partitioner)
+	if err := p.Creates(NewPartition("p1", "1", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	has, err := p.HasPartition(NewPartition("p1", "", ""))
+	if err != nil {
+		t.Fatal("error has partition.", err.Error())
+	}
+
+	if !has {
+		t.Fatal("error hasn't partition.")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test14 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewListPartitioner(db, "test14", "event_id").(*partitioner)
+	if err := p.Creates(NewPartition("p1", "1", ""), NewPartition("p2", "2", ""), NewPartition("p3", "3,4", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	partitions, err := p.ExplainPartitions("SELECT * FROM test14 WHERE event_id = ?", 2)
+	if err != nil {
+		t.Fatal("error explain partitions.", err.Error())
+	}
+
+	if len(partitions) != 1 || partitions[0] != "p2" {
+		t.Fatalf("error invalid partitions. got:%v want:[p2]", partitions)
+	}
+
+	if err := p.AssertPrunedTo("SELECT * FROM test14 WHERE event_id IN (?, ?)", []interface{}{1, 4}, []string{"p3", "p1"}); err != nil {
+		t.Fatal("error assert pruned to.", err.Error())
+	}
+
+	if err := p.AssertPrunedTo("SELECT * FROM test14", nil, []string{"p1"}); err == nil {
+		t.Fatal("error full scan must be error.")
+	}
+
+	partitions, err = p.ExplainPartitions("SELECT * FROM test14 e WHERE e.event_id = ?", 1)
+	if err != nil {
+		t.Fatal("error explain partitions.", err.Error())
+	}
+
+	if len(partitions) != 1 || partitions[0] != "p1" {
+		t.Fatalf("error invalid partitions of aliased table. got:%v want:[p1]", partitions)
+	}
+
+	if _, err := db.Exec("CREATE TABLE test14_other (id INTEGER NOT NULL) PARTITION BY HASH (id) PARTITIONS 2"); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	if _, err := p.ExplainPartitions("SELECT * FROM test14_other WHERE id = 1"); err == nil {
+		t.Fatal("error query without the table must be error.")
+	}
+}
+
+func TestLocate(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test15 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      created_at DATETIME NOT NULL,
+      PRIMARY KEY (id, created_at)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewRangePartitioner(db, "test15", "TO_DAYS(created_at)", CatchAllPartitionName("pmax")).(*partitioner)
+	if err := p.Creates(NewRangePartitionTime("p201001", time.Date(2010, 2, 1, 0, 0, 0, 0, time.UTC)), NewRangePartitionTime("p201002", time.Date(2010, 3, 1, 0, 0, 0, 0, time.UTC))); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	l, err := p.Locator()
+	if err != nil {
+		t.Fatal("error locator.", err.Error())
+	}
+
+	for _, value := range []string{"2010-01-31 23:59:59", "2010-02-01 00:00:00", "2010-03-01 00:00:00"} {
+		expect, found, err := l.PartitionFor(value)
+		if err != nil {
+			t.Fatal("error partition for.", err.Error())
+		}
+
+		if !found {
+			t.Fatalf("error partition for %s is not found.", value)
+		}
+
+		if err := p.AssertPrunedTo("SELECT * FROM test15 WHERE created_at = ?", []interface{}{value}, []string{expect}); err != nil {
+			t.Fatal("error assert pruned to.", err.Error())
+		}
+	}
+}
+
+func TestShowCreateTable(t *testing.T) {
+	mysqld, err := mysqltest.NewMysqld(nil)
+	if err != nil {
+		t.Fatal("error new mysqld.", err.Error())
+	}
+	defer mysqld.Stop()
+
+	db, err := sql.Open("mysql", mysqld.Datasource("test", "", "", 0))
+	if err != nil {
+		t.Fatal("error open.", err.Error())
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test16 (
+      id BIGINT unsigned NOT NULL auto_increment,
+      event_id INTEGER NOT NULL,
+      PRIMARY KEY (id, event_id)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	p := NewListPartitioner(db, "test16", "event_id", ShowCreateTable(true), SubpartitionBy("hash", "id", 2)).(*partitioner)
+
+	partitioned, err := p.IsPartitioned()
+	if err != nil {
+		t.Fatal("error is partitioned.", err.Error())
+	}
+
+	if partitioned {
+		t.Fatal("error invalid status.")
+	}
+
+	if err := p.Creates(NewPartition("p1", "1", "first")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	partitioned, err = p.IsPartitioned()
+	if err != nil {
+		t.Fatal("error is partitioned.", err.Error())
+	}
+
+	if !partitioned {
+		t.Fatal("error invalid status.")
+	}
+
+	has, err := p.HasPartition(NewPartition("p1", "", "", NewSubpartition("p1sp1", "")))
+	if err != nil {
+		t.Fatal("error has partition.", err.Error())
+	}
+
+	if !has {
+		t.Fatal("error hasn't partition.")
+	}
+
+	infos, err := p.Partitions()
+	if err != nil {
+		t.Fatal("error partitions.", err.Error())
+	}
+
+	if len(infos) != 1 || infos[0].Comment != "first" || infos[0].Engine != "InnoDB" {
+		t.Fatalf("error invalid partitions. %#v", infos)
+	}
+	desc, err := p.retrieveDescription(context.Background(), "p1")
+	if err != nil {
+		t.Fatal("error retrieve description.", err.Error())
+	}
+
+	if desc != "1" {
+		t.Fatalf("error invalid description %s.", desc)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE test16_range (
+      id BIGINT unsigned NOT NULL auto_increment,
+      created_at DATE NOT NULL,
+      PRIMARY KEY (id, created_at)
+    )`); err != nil {
+		t.Fatal("error exec sceham.", err.Error())
+	}
+
+	r := NewRangePartitioner(db, "test16_range", "created_at", Type("range columns"), ShowCreateTable(true), CatchAllPartitionName("pmax")).(*partitioner)
+	if err := r.Creates(NewPartition("p20240101", "2024-01-01", "")); err != nil {
+		t.Fatal("error creates.", err.Error())
+	}
+
+	name, err := r.retrieveCatchAllPartition(context.Background())
+	if err != nil {
+		t.Fatal("error retrieve catch all partition.", err.Error())
+	}
+
+	if name != "pmax" {
+		t.Fatalf("error invalid catch all partition %s.", name)
+	}
 }
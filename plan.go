@@ -0,0 +1,369 @@
+package partition
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Action is kind of plan step
+type Action string
+
+const (
+	// ActionCreate partitions not partitioned table
+	ActionCreate Action = "create"
+	// ActionAdd adds partitions
+	ActionAdd Action = "add"
+	// ActionDrop drops partitions
+	ActionDrop Action = "drop"
+	// ActionReorganize reorganizes partitions
+	ActionReorganize Action = "reorganize"
+)
+
+// Step is a step of plan.
+// From is current partitions to be dropped or reorganized and Into is partitions to be created, added or reorganized into.
+type Step struct {
+	Action  Action
+	From    []*Partition
+	Into    []*Partition
+	Handler Handler
+}
+
+// Plan is ordered steps to make partitions of the table desired state.
+// Plan is also Handler which executes all steps in order.
+type Plan struct {
+	Steps []*Step
+
+	current    map[string]*PartitionInfo
+	expression string
+	describe   func(string) string
+}
+
+// Planner compares desired partitions with current partitions and makes plan
+type Planner struct {
+	partitioner planPartitioner
+}
+
+// planPartitioner is partitioner which can be planned.
+// partitioners returned by constructors of this package implement it.
+type planPartitioner interface {
+	ContextPartitioner
+	Inspector
+	Reorganizer
+	prepareAdds(ctx context.Context, dropped []*Partition, partitions ...*Partition) (Handler, error)
+	prepareReorganizes(ctx context.Context, dropped []*Partition, from []*Partition, into []*Partition) (Handler, error)
+	resolvePartitions([]*Partition) ([]*Partition, error)
+	describe(description string) string
+}
+
+// NewPlanner is XXX
+func NewPlanner(p Partitioner) *Planner {
+	planner, _ := p.(planPartitioner)
+	return &Planner{
+		partitioner: planner,
+	}
+}
+
+// Plan returns plan to make partitions of the table desired.
+// Descriptions are compared with information_schema textually ignoring white spaces,
+// so expressions such as TO_DAYS('2010-01-01') should be specified as stored value like 734138
+// or as time.Time by NewRangePartitionTime.
+// Upper bound of the last range partition can't be changed because reorganize must keep the range it covers,
+// so add a partition after it or use catch all partition instead.
+func (pl *Planner) Plan(desired ...*Partition) (*Plan, error) {
+	return pl.PlanContext(context.Background(), desired...)
+}
+
+// PlanContext is Plan with context
+func (pl *Planner) PlanContext(ctx context.Context, desired ...*Partition) (*Plan, error) {
+	if pl.partitioner == nil {
+		return nil, fmt.Errorf("error partitioner doesn't support planning")
+	}
+
+	names := map[string]bool{}
+	for _, partition := range desired {
+		if names[partition.Name] {
+			return nil, fmt.Errorf("error duplicate partition name %s", partition.Name)
+		}
+		names[partition.Name] = true
+	}
+
+	infos, err := pl.partitioner.PartitionsContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error Partitions")
+	}
+
+	plan := &Plan{current: map[string]*PartitionInfo{}, describe: pl.partitioner.describe}
+	for _, info := range infos {
+		plan.current[info.Name] = info
+	}
+
+	if len(infos) == 0 {
+		if len(desired) == 0 {
+			return plan, nil
+		}
+
+		h, err := pl.partitioner.PrepareCreatesContext(ctx, desired...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error PrepareCreates")
+		}
+
+		into, err := pl.partitioner.resolvePartitions(desired)
+		if err != nil {
+			return nil, errors.Wrap(err, "error resolvePartitions")
+		}
+		plan.Steps = append(plan.Steps, &Step{Action: ActionCreate, Into: into, Handler: h})
+		return plan, nil
+	}
+
+	method := infos[0].Method
+	if strings.Contains(method, PartitionTypeHash) || strings.Contains(method, PartitionTypeKey) {
+		return nil, fmt.Errorf("error plan is not supported for %s partition", method)
+	}
+
+	// typed values are compared as written by partitioning of the table
+	desired, err = resolvePartitions(desired, method, infos[0].Expression)
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolvePartitions")
+	}
+	plan.expression = infos[0].Expression
+
+	drops := []*Partition{}
+	for _, info := range infos {
+		if !names[info.Name] {
+			drops = append(drops, NewPartition(info.Name, info.Description, info.Comment))
+		}
+	}
+
+	if 0 < len(drops) {
+		h, err := pl.partitioner.PrepareDropsContext(ctx, drops...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error PrepareDrops")
+		}
+		plan.Steps = append(plan.Steps, &Step{Action: ActionDrop, From: drops, Handler: h})
+	}
+
+	var steps []*Step
+	if strings.HasPrefix(method, PartitionTypeRange) {
+		steps, err = pl.planRange(ctx, plan, drops, infos, desired)
+	} else {
+		steps, err = pl.planList(ctx, plan, drops, desired)
+	}
+	if err != nil {
+		return nil, err
+	}
+	plan.Steps = append(plan.Steps, steps...)
+
+	return plan, nil
+}
+
+// planRange splits or changes existing partitions by reorganize and adds partitions after last partition
+func (pl *Planner) planRange(ctx context.Context, plan *Plan, dropped []*Partition, infos []*PartitionInfo, desired []*Partition) ([]*Step, error) {
+	position := map[string]int{}
+	for i, info := range infos {
+		position[info.Name] = i
+	}
+
+	steps := []*Step{}
+	from, into := []*Partition{}, []*Partition{}
+	last := -1
+	for _, partition := range desired {
+		info, ok := plan.current[partition.Name]
+		if !ok {
+			into = append(into, partition)
+			continue
+		}
+
+		if position[info.Name] < last {
+			return nil, fmt.Errorf("error order of partition %s is changed", partition.Name)
+		}
+		last = position[info.Name]
+
+		from = append(from, NewPartition(info.Name, info.Description, info.Comment))
+		into = append(into, partition)
+
+		// upper bound of the partition is kept, so partitions until here can be reorganized
+		if plan.changed(partition) {
+			continue
+		}
+
+		if 1 < len(into) || 1 < len(from) {
+			step, err := pl.reorganize(ctx, dropped, from, into)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		}
+		from, into = []*Partition{}, []*Partition{}
+	}
+
+	switch {
+	case 0 < len(from):
+		step, err := pl.reorganize(ctx, dropped, from, into)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	case 0 < len(into):
+		h, err := pl.partitioner.prepareAdds(ctx, dropped, into...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error PrepareAdds")
+		}
+		steps = append(steps, &Step{Action: ActionAdd, Into: into, Handler: h})
+	}
+
+	return steps, nil
+}
+
+// planList reorganizes changed partitions at once to move values between them and adds new partitions
+func (pl *Planner) planList(ctx context.Context, plan *Plan, dropped []*Partition, desired []*Partition) ([]*Step, error) {
+	steps := []*Step{}
+	from, into, adds := []*Partition{}, []*Partition{}, []*Partition{}
+	for _, partition := range desired {
+		info, ok := plan.current[partition.Name]
+		if !ok {
+			adds = append(adds, partition)
+			continue
+		}
+
+		if plan.changed(partition) {
+			from = append(from, NewPartition(info.Name, info.Description, info.Comment))
+			into = append(into, partition)
+		}
+	}
+
+	if 0 < len(from) {
+		step, err := pl.reorganize(ctx, dropped, from, into)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	if 0 < len(adds) {
+		h, err := pl.partitioner.prepareAdds(ctx, dropped, adds...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error PrepareAdds")
+		}
+		steps = append(steps, &Step{Action: ActionAdd, Into: adds, Handler: h})
+	}
+
+	return steps, nil
+}
+
+func (pl *Planner) reorganize(ctx context.Context, dropped, from, into []*Partition) (*Step, error) {
+	h, err := pl.partitioner.prepareReorganizes(ctx, dropped, from, into)
+	if err != nil {
+		return nil, errors.Wrap(err, "error PrepareReorganizes")
+	}
+
+	return &Step{Action: ActionReorganize, From: from, Into: into, Handler: h}, nil
+}
+
+// changed reports whether description or comment of existing partition is changed
+func (plan *Plan) changed(partition *Partition) bool {
+	info, ok := plan.current[partition.Name]
+	if !ok {
+		return true
+	}
+
+	if partition.Comment != info.Comment {
+		return true
+	}
+
+	current := normalizeDescription(info.Description)
+	if current == normalizeDescription(partition.Description) {
+		return false
+	}
+
+	// information_schema has evaluated value such as 734138 for TO_DAYS('2010-01-01')
+	if len(partition.Values) == 1 {
+		if t, ok := partition.Values[0].(time.Time); ok {
+			value, ok := storedTimeValue(t, plan.expression)
+			return !ok || current != value
+		}
+	}
+
+	if 0 < len(partition.Values) {
+		return true
+	}
+
+	// range partition is described with quote in information_schema
+	value, err := (&Range{}).value(partition.Description)
+	return err != nil || current != normalizeDescription(value)
+}
+
+func normalizeDescription(description string) string {
+	return strings.Join(strings.Fields(description), "")
+}
+
+// Empty reports whether the plan has no step
+func (plan *Plan) Empty() bool {
+	return len(plan.Steps) == 0
+}
+
+// Diff returns human readable difference between current and desired partitions.
+// + is partition to be created, - is to be dropped and ~ is to be changed.
+// descriptions are written as in the statement.
+func (plan *Plan) Diff() string {
+	buf := &bytes.Buffer{}
+	for _, step := range plan.Steps {
+		for _, partition := range step.From {
+			if step.Action == ActionDrop {
+				fmt.Fprintf(buf, "- %s (%s)\n", partition.Name, plan.describe(partition.Description))
+			}
+		}
+
+		for _, partition := range step.Into {
+			info, ok := plan.current[partition.Name]
+			switch {
+			case !ok:
+				fmt.Fprintf(buf, "+ %s (%s)\n", partition.Name, plan.describe(partition.Description))
+			case plan.changed(partition):
+				fmt.Fprintf(buf, "~ %s (%s) -> (%s)\n", partition.Name, plan.describe(info.Description), plan.describe(partition.Description))
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+// describe returns description of existing or requested partition as written in the statement
+func (p *partitioner) describe(description string) string {
+	if r, ok := p.partBuilder.(*Range); ok {
+		if value, err := r.value(description); err == nil {
+			return value
+		}
+		return description
+	}
+
+	return strings.Join(splitColumns(description), ", ")
+}
+
+func (plan *Plan) handlers() handlers {
+	hs := handlers{}
+	for _, step := range plan.Steps {
+		hs = append(hs, step.Handler)
+	}
+
+	return hs
+}
+
+// Execute executes all steps in order
+func (plan *Plan) Execute() error {
+	return plan.ExecuteContext(context.Background())
+}
+
+// ExecuteContext is Execute with context
+func (plan *Plan) ExecuteContext(ctx context.Context) error {
+	return plan.handlers().ExecuteContext(ctx)
+}
+
+// Statement returns statements of all steps
+func (plan *Plan) Statement() string {
+	return plan.handlers().Statement()
+}
@@ -0,0 +1,155 @@
+package partition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlanner(t *testing.T) {
+	type Test struct {
+		Title       string
+		Partitioner Partitioner
+		Current     []*PartitionInfo
+		Desired     []*Partition
+		Statement   string
+		Diff        string
+	}
+
+	rangeColumns := NewRangePartitioner(nil, "events", "created_at", Type("range columns"))
+	list := NewListPartitioner(nil, "events", "event_id")
+	toDays := NewRangePartitioner(nil, "events", "TO_DAYS(created_at)")
+
+	tests := []Test{
+		Test{
+			Title:       "create",
+			Partitioner: rangeColumns,
+			Desired:     []*Partition{NewPartition("p1", "2024-01-01", ""), NewPartition("pmax", CatchAllPartitionValue, "")},
+			Statement:   "ALTER TABLE `events` PARTITION BY RANGE COLUMNS (created_at) (PARTITION `p1` VALUES LESS THAN ('2024-01-01'), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))",
+			Diff:        "+ p1 ('2024-01-01')\n+ pmax (MAXVALUE)\n",
+		},
+		Test{
+			Title:       "no change",
+			Partitioner: rangeColumns,
+			Current: []*PartitionInfo{
+				&PartitionInfo{Name: "p1", Method: "RANGE COLUMNS", Description: "'2024-01-01'"},
+			},
+			Desired:   []*Partition{NewPartition("p1", "2024-01-01", "")},
+			Statement: "",
+			Diff:      "",
+		},
+		Test{
+			Title:       "range drop, split and add",
+			Partitioner: rangeColumns,
+			Current: []*PartitionInfo{
+				&PartitionInfo{Name: "p0", Method: "RANGE COLUMNS", Description: "'2023-12-01'"},
+				&PartitionInfo{Name: "p1", Method: "RANGE COLUMNS", Description: "'2024-01-01'"},
+				&PartitionInfo{Name: "p3", Method: "RANGE COLUMNS", Description: "'2024-03-01'"},
+			},
+			Desired: []*Partition{
+				NewPartition("p1", "2024-01-01", ""),
+				NewPartition("p2", "2024-02-01", ""),
+				NewPartition("p3", "2024-03-01", ""),
+				NewPartition("p4", "2024-04-01", ""),
+			},
+			Statement: "ALTER TABLE `events` DROP PARTITION `p0`;\n" +
+				"ALTER TABLE `events` REORGANIZE PARTITION `p3` INTO (PARTITION `p2` VALUES LESS THAN ('2024-02-01'), PARTITION `p3` VALUES LESS THAN ('2024-03-01'));\n" +
+				"ALTER TABLE `events` ADD PARTITION (PARTITION `p4` VALUES LESS THAN ('2024-04-01'))",
+			Diff: "- p0 ('2023-12-01')\n+ p2 ('2024-02-01')\n+ p4 ('2024-04-01')\n",
+		},
+		Test{
+			Title:       "range split catch all",
+			Partitioner: rangeColumns,
+			Current: []*PartitionInfo{
+				&PartitionInfo{Name: "p1", Method: "RANGE COLUMNS", Description: "'2024-01-01'"},
+				&PartitionInfo{Name: "pmax", Method: "RANGE COLUMNS", Description: "MAXVALUE"},
+			},
+			Desired: []*Partition{
+				NewPartition("p1", "2024-01-01", ""),
+				NewPartition("p2", "2024-02-01", ""),
+				NewPartition("pmax", CatchAllPartitionValue, ""),
+			},
+			Statement: "ALTER TABLE `events` REORGANIZE PARTITION `pmax` INTO (PARTITION `p2` VALUES LESS THAN ('2024-02-01'), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))",
+			Diff:      "+ p2 ('2024-02-01')\n",
+		},
+		Test{
+			Title:       "list move values and add",
+			Partitioner: list,
+			Current: []*PartitionInfo{
+				&PartitionInfo{Name: "p1", Method: "LIST", Description: "1,2"},
+				&PartitionInfo{Name: "p2", Method: "LIST", Description: "3"},
+			},
+			Desired: []*Partition{
+				NewPartition("p1", "1", ""),
+				NewPartition("p2", "2, 3", ""),
+				NewPartition("p4", "4", ""),
+			},
+			Statement: "ALTER TABLE `events` REORGANIZE PARTITION `p1`,`p2` INTO (PARTITION `p1` VALUES IN (1), PARTITION `p2` VALUES IN (2, 3));\n" +
+				"ALTER TABLE `events` ADD PARTITION (PARTITION `p4` VALUES IN (4))",
+			Diff: "~ p1 (1, 2) -> (1)\n~ p2 (3) -> (2, 3)\n+ p4 (4)\n",
+		},
+		Test{
+			Title:       "range typed values",
+			Partitioner: toDays,
+			Current: []*PartitionInfo{
+				&PartitionInfo{Name: "p201001", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "734138"},
+			},
+			Desired: []*Partition{
+				NewRangePartitionTime("p201001", time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)),
+				NewRangePartitionTime("p201002", time.Date(2010, 2, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			Statement: "ALTER TABLE `events` ADD PARTITION (PARTITION `p201002` VALUES LESS THAN (TO_DAYS('2010-02-01')))",
+			Diff:      "+ p201002 (TO_DAYS('2010-02-01'))\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			p := &stubPartitioner{
+				partitioner: test.Partitioner.(*partitioner),
+				infos:       test.Current,
+			}
+
+			plan, err := NewPlanner(p).Plan(test.Desired...)
+			if err != nil {
+				t.Fatal("error plan.", err.Error())
+			}
+
+			if diff := cmp.Diff(plan.Statement(), test.Statement); diff != "" {
+				t.Fatalf("error invalid statement:%s", diff)
+			}
+
+			if diff := cmp.Diff(plan.Diff(), test.Diff); diff != "" {
+				t.Fatalf("error invalid diff:%s", diff)
+			}
+		})
+	}
+
+	t.Run("last range bound changed", func(t *testing.T) {
+		p := &stubPartitioner{
+			partitioner: rangeColumns.(*partitioner),
+			infos: []*PartitionInfo{
+				&PartitionInfo{Name: "p1", Method: "RANGE COLUMNS", Description: "'2024-01-01'"},
+			},
+		}
+
+		if _, err := NewPlanner(p).Plan(NewPartition("p1", "2024-02-01", "")); err == nil {
+			t.Fatal("error changed last bound must be error.")
+		}
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		p := &stubPartitioner{
+			partitioner: rangeColumns.(*partitioner),
+			infos: []*PartitionInfo{
+				&PartitionInfo{Name: "p1", Method: "RANGE COLUMNS", Description: "'2024-01-01'"},
+				&PartitionInfo{Name: "p2", Method: "RANGE COLUMNS", Description: "'2024-02-01'"},
+			},
+		}
+
+		if _, err := NewPlanner(p).Plan(NewPartition("p2", "2024-02-01", ""), NewPartition("p1", "2024-01-01", "")); err == nil {
+			t.Fatal("error changed order must be error.")
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package partition
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxIdentifierLength = 64
+
+// validateIdentifier checks name against MySQL's rules for quoted identifiers
+func validateIdentifier(name string) error {
+	if name == "" {
+		return fmt.Errorf("error identifier is empty")
+	}
+
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("error identifier %q is not valid utf8", name)
+	}
+
+	if maxIdentifierLength < utf8.RuneCountInString(name) {
+		return fmt.Errorf("error identifier %q is longer than %d characters", name, maxIdentifierLength)
+	}
+
+	// identifiers can't end with space and can't contain NUL and supplementary characters
+	if strings.HasSuffix(name, " ") {
+		return fmt.Errorf("error identifier %q ends with space", name)
+	}
+
+	for _, r := range name {
+		if r == 0 || 0xFFFF < r {
+			return fmt.Errorf("error identifier %q contains invalid character %U", name, r)
+		}
+	}
+
+	return nil
+}
+
+// quoteIdentifier quotes name with backtick
+func quoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// unquoteIdentifier is reverse of quoteIdentifier for name inside backticks
+func unquoteIdentifier(name string) string {
+	return strings.Replace(name, "``", "`", -1)
+}
+
+// quoteName validates and quotes partition, subpartition or table name
+func quoteName(name string) (string, error) {
+	if err := validateIdentifier(name); err != nil {
+		return "", err
+	}
+
+	return quoteIdentifier(name), nil
+}
+
+// splitTable splits schema.table. schema is empty when table is not qualified.
+func splitTable(table string) (string, string) {
+	names := strings.SplitN(table, ".", 2)
+	if len(names) == 1 {
+		return "", names[0]
+	}
+
+	return names[0], names[1]
+}
+
+// quoteTable validates and quotes table name. schema.table is quoted as `schema`.`table`.
+func quoteTable(table string) (string, error) {
+	names := strings.SplitN(table, ".", 2)
+
+	quoted := []string{}
+	for _, name := range names {
+		q, err := quoteName(name)
+		if err != nil {
+			return "", err
+		}
+		quoted = append(quoted, q)
+	}
+
+	return strings.Join(quoted, "."), nil
+}
+
+// quoteString escapes s as MySQL string literal.
+// quote is doubled so that the literal is not closed even with NO_BACKSLASH_ESCAPES.
+func quoteString(s string) string {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			buf.WriteString(`\0`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\x1a':
+			buf.WriteString(`\Z`)
+		case '\'':
+			buf.WriteString(`''`)
+		case '\\':
+			buf.WriteString(`\\`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('\'')
+
+	return buf.String()
+}
+
+// validateExpression checks expression written in raw SQL doesn't break out of the statement.
+// quotes and brackets must be balanced and statement terminator and comments are not allowed.
+func validateExpression(expr string) error {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if quote != 0 {
+			switch {
+			case c == '\\' && quote != '`':
+				i++
+			case c == quote && i+1 < len(expr) && expr[i+1] == quote:
+				i++
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("error unbalanced bracket in %q", expr)
+			}
+		case ';', '#', 0:
+			return fmt.Errorf("error invalid character %q in %q", c, expr)
+		case '-', '/':
+			if i+1 < len(expr) && ((c == '-' && expr[i+1] == '-') || (c == '/' && expr[i+1] == '*')) {
+				return fmt.Errorf("error comment is not allowed in %q", expr)
+			}
+		case '*':
+			if i+1 < len(expr) && expr[i+1] == '/' {
+				return fmt.Errorf("error comment is not allowed in %q", expr)
+			}
+		}
+	}
+
+	if quote != 0 {
+		return fmt.Errorf("error unclosed quote in %q", expr)
+	}
+
+	if depth != 0 {
+		return fmt.Errorf("error unbalanced bracket in %q", expr)
+	}
+
+	return nil
+}
+
+// buildComment returns COMMENT clause
+func buildComment(comment string) string {
+	if comment == "" {
+		return ""
+	}
+
+	return " COMMENT = " + quoteString(comment)
+}
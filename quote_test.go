@@ -0,0 +1,93 @@
+package partition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_quoteTable(t *testing.T) {
+	tests := map[string]string{
+		"order":            "`order`",
+		"event-log":        "`event-log`",
+		"analytics.events": "`analytics`.`events`",
+		"we`ird":           "`we``ird`",
+	}
+
+	for input, expect := range tests {
+		result, err := quoteTable(input)
+		if err != nil {
+			t.Fatal("error quote table.", err.Error())
+		}
+
+		if diff := cmp.Diff(result, expect); diff != "" {
+			t.Fatalf("error invalid result:%s", diff)
+		}
+	}
+
+	for _, input := range []string{"", "trailing ", "nul\x00", strings.Repeat("a", 65), "emoji\U0001F600"} {
+		if _, err := quoteTable(input); err == nil {
+			t.Fatalf("error %q must be invalid.", input)
+		}
+	}
+}
+
+func Test_quoteString(t *testing.T) {
+	expect := `'it''s \\ "new"\nline'`
+	if diff := cmp.Diff(quoteString("it's \\ \"new\"\nline"), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+}
+
+func Test_validateExpression(t *testing.T) {
+	for _, expr := range []string{"1", "2, 3", "TO_DAYS('2010-01-01')", "'a;b', 'c--d'", "'it''s'", "`col`"} {
+		if err := validateExpression(expr); err != nil {
+			t.Fatalf("error %q must be valid. %s", expr, err.Error())
+		}
+	}
+
+	for _, expr := range []string{"1); DROP TABLE test; --", "1) -- ", "1 /* */", "1 # x", "(1", "1)", "'a"} {
+		if err := validateExpression(expr); err == nil {
+			t.Fatalf("error %q must be invalid.", expr)
+		}
+	}
+}
+
+func TestQuotedStatement(t *testing.T) {
+	r := NewRangePartitioner(nil, "analytics.order-events", "created_at", Type("range columns"))
+
+	h, err := r.PrepareCreates(NewPartition("p2010", "2010-01-01'); DROP TABLE test; --", "it's comment"))
+	if err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	expect := "ALTER TABLE `analytics`.`order-events` PARTITION BY RANGE COLUMNS (created_at) (PARTITION `p2010` VALUES LESS THAN ('2010-01-01''); DROP TABLE test; --') COMMENT = 'it''s comment')"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	// backslash can't escape doubled quote under NO_BACKSLASH_ESCAPES
+	h, err = r.PrepareCreates(NewPartition("p2010", `x\'); DROP TABLE test; --`, ""))
+	if err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	expect = "ALTER TABLE `analytics`.`order-events` PARTITION BY RANGE COLUMNS (created_at) (PARTITION `p2010` VALUES LESS THAN ('x\\\\''); DROP TABLE test; --'))"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	l := NewListPartitioner(nil, "test", "event_id")
+	if _, err := l.PrepareAdds(NewPartition("p1", "1); DROP TABLE test; --", "")); err == nil {
+		t.Fatal("error injected description must be error.")
+	}
+
+	if _, err := l.PrepareDrops(NewPartition("p1 ", "", "")); err == nil {
+		t.Fatal("error invalid partition name must be error.")
+	}
+
+	if _, err := NewListPartitioner(nil, "test", "event_id", Type("list; drop")).PrepareCreates(NewPartition("p1", "1", "")); err == nil {
+		t.Fatal("error invalid type must be error.")
+	}
+}
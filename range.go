@@ -1,10 +1,13 @@
 package partition
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // Range is range partition part builder
@@ -16,15 +19,37 @@ type Range struct {
 var (
 	numberRegexp  *regexp.Regexp
 	bracketRegexp *regexp.Regexp
+	methodRegexp  *regexp.Regexp
 )
 
 func init() {
 	numberRegexp = regexp.MustCompile(`^[0-9]+$`)
 	bracketRegexp = regexp.MustCompile(`\(`)
+	methodRegexp = regexp.MustCompile(`^(LINEAR )?(RANGE|LIST|HASH|KEY)( COLUMNS)?$`)
+}
+
+// RangePartitioner is partitioner for range partition which also manages catch all partition
+type RangePartitioner interface {
+	Partitioner
+
+	HasCatchAllPartition() (bool, error)
+	AddCatchAllPartition() error
+	ReorganizeCatchAllPartition(...*Partition) error
+
+	HasCatchAllPartitionContext(context.Context) (bool, error)
+	AddCatchAllPartitionContext(context.Context) error
+	ReorganizeCatchAllPartitionContext(context.Context, ...*Partition) error
+
+	PrepareAddCatchAllPartition() (Handler, error)
+	PrepareReorganizeCatchAllPartition(...*Partition) (Handler, error)
+
+	PrepareAddCatchAllPartitionContext(context.Context) (Handler, error)
+	PrepareReorganizeCatchAllPartitionContext(context.Context, ...*Partition) (Handler, error)
 }
 
 // NewRangePartitioner is XXX
-func NewRangePartitioner(db *sql.DB, table, expresstion string, options ...Option) Partitioner {
+// When the table has catch all partition, Adds reorganizes it instead of adding partitions.
+func NewRangePartitioner(db *sql.DB, table, expresstion string, options ...Option) RangePartitioner {
 	p := &partitioner{
 		table:         table,
 		db:            db,
@@ -38,36 +63,292 @@ func NewRangePartitioner(db *sql.DB, table, expresstion string, options ...Optio
 	for _, option := range options {
 		option(p)
 	}
+	p.resolveTable()
 
 	return p
 }
 
+// NewRangeColumnsPartitioner is range columns partitioner whose partitions are bounded by values of the columns
+func NewRangeColumnsPartitioner(db *sql.DB, table string, columns []string, options ...Option) RangePartitioner {
+	options = append([]Option{Type(PartitionTypeRange + " COLUMNS")}, options...)
+	return NewRangePartitioner(db, table, quoteColumns(columns), options...)
+}
+
 func (r *Range) buildPart(p *Partition) (string, error) {
 	if p.Description == "" {
 		return "", fmt.Errorf("error no partition description is spcified")
 	}
 
-	if !numberRegexp.MatchString(p.Description) && p.Description != CatchAllPartitionValue && !bracketRegexp.MatchString(p.Description) {
-		p.Description = fmt.Sprintf("'%s'", p.Description)
+	value, err := r.partitionValue(p)
+	if err != nil {
+		return "", err
+	}
+
+	name, err := quoteName(p.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("PARTITION %s VALUES LESS THAN (%s)", name, value) + buildComment(p.Comment), nil
+}
+
+// partitionValue returns value of the partition.
+// description built from typed values is already quoted properly.
+func (r *Range) partitionValue(p *Partition) (string, error) {
+	if len(p.Values) == 0 {
+		return r.value(p.Description)
 	}
 
-	part := fmt.Sprintf("PARTITION %s VALUES LESS THAN (%s)", p.Name, p.Description)
-	if p.Comment != "" {
-		part = part + fmt.Sprintf(" COMMENT = '%s'", strings.Replace(p.Comment, "'", "", -1))
+	if err := validateExpression(p.Description); err != nil {
+		return "", err
 	}
 
-	return part, nil
+	return p.Description, nil
 }
 
-func (r *Range) buildCatchAllPart() (string, error) {
-	if r.catchAllPartitionName == "" {
+// value returns description quoted if needed.
+// description already quoted, number and expression are written as is.
+// values of multiple columns separated by comma are quoted each.
+func (r *Range) value(description string) (string, error) {
+	if values := splitColumns(description); 1 < len(values) {
+		quoted := []string{}
+		for _, value := range values {
+			v, err := r.value(value)
+			if err != nil {
+				return "", err
+			}
+			quoted = append(quoted, v)
+		}
+		return strings.Join(quoted, ", "), nil
+	}
+
+	if !strings.HasPrefix(description, "'") && !numberRegexp.MatchString(description) && description != CatchAllPartitionValue && !bracketRegexp.MatchString(description) {
+		return quoteString(description), nil
+	}
+
+	if err := validateExpression(description); err != nil {
+		return "", err
+	}
+
+	return description, nil
+}
+
+func (r *Range) buildCatchAllPart(catchAll *Partition) (string, error) {
+	if catchAll.Name == "" {
 		return "", fmt.Errorf("catch_all_partition_name isn't specified")
 	}
 
-	part, err := r.buildPart(&Partition{Name: r.catchAllPartitionName, Description: "MAXVALUE"})
+	part, err := r.buildPart(catchAll)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("ALTER TABLE %s ADD PARTITION (%s)", r.table, part), nil
+	table, err := quoteTable(r.table)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s ADD PARTITION (%s)", table, part), nil
+}
+
+// catchAllPartition returns catch all partition whose values are MAXVALUE for all columns
+func (p *partitioner) catchAllPartition(name string) *Partition {
+	values := []interface{}{}
+	descriptions := []string{}
+	for i := 0; i < p.columnCount(); i++ {
+		values = append(values, MaxValue)
+		descriptions = append(descriptions, CatchAllPartitionValue)
+	}
+
+	return &Partition{
+		Name:        name,
+		Description: strings.Join(descriptions, ", "),
+		Values:      values,
+	}
+}
+
+func (p *partitioner) rangeBuilder() (*Range, error) {
+	r, ok := p.partBuilder.(*Range)
+	if !ok {
+		return nil, fmt.Errorf("error catch all partition is supported only for range partition")
+	}
+
+	return r, nil
+}
+
+// retrieveCatchAllPartition returns name of catch all partition. it returns empty when table has no catch all partition.
+func (p *partitioner) retrieveCatchAllPartition(ctx context.Context) (string, error) {
+	if p.showCreateTable {
+		infos, err := p.PartitionsContext(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "error Partitions")
+		}
+
+		last := len(infos) - 1
+		if last < 0 || infos[last].Method != p.partitionType || !strings.HasPrefix(infos[last].Description, CatchAllPartitionValue) {
+			return "", nil
+		}
+
+		return infos[last].Name, nil
+	}
+
+	dbName, err := p.dbName(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "error dbName")
+	}
+
+	var name, desc sql.NullString
+	err = p.db.QueryRowContext(ctx, `
+SELECT
+  partition_name, partition_description
+FROM
+  information_schema.PARTITIONS
+WHERE
+  table_name		= ? AND
+  table_schema		= ? AND
+  partition_method	= ?
+ORDER BY
+  partition_ordinal_position DESC
+LIMIT 1
+`, p.table, dbName, p.partitionType).Scan(&name, &desc)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "error select last partition")
+	}
+
+	// RANGE COLUMNS partition with multiple columns is described like MAXVALUE,MAXVALUE
+	if !strings.HasPrefix(desc.String, CatchAllPartitionValue) {
+		return "", nil
+	}
+
+	return name.String, nil
+}
+
+func (p *partitioner) HasCatchAllPartition() (bool, error) {
+	return p.HasCatchAllPartitionContext(context.Background())
+}
+
+func (p *partitioner) HasCatchAllPartitionContext(ctx context.Context) (bool, error) {
+	if _, err := p.rangeBuilder(); err != nil {
+		return false, err
+	}
+
+	name, err := p.retrieveCatchAllPartition(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "error retrieveCatchAllPartition")
+	}
+
+	return name != "", nil
+}
+
+func (p *partitioner) AddCatchAllPartition() error {
+	return p.AddCatchAllPartitionContext(context.Background())
+}
+
+func (p *partitioner) AddCatchAllPartitionContext(ctx context.Context) error {
+	h, err := p.PrepareAddCatchAllPartitionContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error PrepareAddCatchAllPartition")
+	}
+	return h.ExecuteContext(ctx)
+}
+
+func (p *partitioner) ReorganizeCatchAllPartition(partitions ...*Partition) error {
+	return p.ReorganizeCatchAllPartitionContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) ReorganizeCatchAllPartitionContext(ctx context.Context, partitions ...*Partition) error {
+	h, err := p.PrepareReorganizeCatchAllPartitionContext(ctx, partitions...)
+	if err != nil {
+		return errors.Wrap(err, "error PrepareReorganizeCatchAllPartition")
+	}
+	return h.ExecuteContext(ctx)
+}
+
+func (p *partitioner) PrepareAddCatchAllPartition() (Handler, error) {
+	return p.PrepareAddCatchAllPartitionContext(context.Background())
+}
+
+func (p *partitioner) PrepareAddCatchAllPartitionContext(ctx context.Context) (Handler, error) {
+	r, err := p.rangeBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := r.buildCatchAllPart(p.catchAllPartition(r.catchAllPartitionName))
+	if err != nil {
+		return nil, errors.Wrap(err, "error buildCatchAllPart")
+	}
+	return p.newHandler(stmt, ""), nil
+}
+
+func (p *partitioner) PrepareReorganizeCatchAllPartition(partitions ...*Partition) (Handler, error) {
+	return p.PrepareReorganizeCatchAllPartitionContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) PrepareReorganizeCatchAllPartitionContext(ctx context.Context, partitions ...*Partition) (Handler, error) {
+	r, err := p.rangeBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	name := r.catchAllPartitionName
+	if p.db != nil {
+		found, err := p.retrieveCatchAllPartition(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error retrieveCatchAllPartition")
+		}
+		if found == "" {
+			return nil, fmt.Errorf("error table %s has no catch all partition", p.qualifiedTable())
+		}
+		name = found
+	}
+
+	return p.prepareReorganizeCatchAll(ctx, nil, name, partitions...)
+}
+
+func (p *partitioner) prepareReorganizeCatchAll(ctx context.Context, dropped []*Partition, name string, partitions ...*Partition) (Handler, error) {
+	if name == "" {
+		return nil, fmt.Errorf("catch_all_partition_name isn't specified")
+	}
+
+	catchAll := p.catchAllPartition(name)
+	// explicit subpartitions must be defined for all partitions
+	if 0 < len(partitions) && 0 < len(partitions[0].Subpartitions) {
+		n := len(partitions[0].Subpartitions)
+		catchAll.Subpartitions = catchAllSubpartitions(name, n)
+
+		// existing subpartitions of catch all partition are kept
+		if p.db != nil {
+			subs, err := p.retrieveSubpartitions(ctx, name)
+			if err != nil {
+				return nil, errors.Wrap(err, "error retrieveSubpartitions")
+			}
+			if len(subs) != n {
+				return nil, fmt.Errorf("error catch all partition %s has %d subpartitions, but %d subpartitions are specified", name, len(subs), n)
+			}
+
+			catchAll.Subpartitions = nil
+			for _, sub := range subs {
+				catchAll.Subpartitions = append(catchAll.Subpartitions, NewSubpartition(sub, ""))
+			}
+		}
+	}
+
+	from := []*Partition{p.catchAllPartition(name)}
+	into := append(append([]*Partition{}, partitions...), catchAll)
+
+	return p.prepareReorganizes(ctx, dropped, from, into)
+}
+
+// catchAllSubpartitions returns n explicit subpartitions of catch all partition named after it
+func catchAllSubpartitions(name string, n int) []*Subpartition {
+	var subs []*Subpartition
+	for i := 0; i < n; i++ {
+		subs = append(subs, NewSubpartition(fmt.Sprintf("%ssp%d", name, i), ""))
+	}
+
+	return subs
 }
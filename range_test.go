@@ -19,7 +19,7 @@ func TestRangePartitioner(t *testing.T) {
 		Test{
 			Title:  "create partition",
 			Input:  []*Partition{NewPartition("p20100101", "2010-01-01", "")},
-			Output: "ALTER TABLE test2 PARTITION BY RANGE COLUMNS (created_at) (PARTITION p20100101 VALUES LESS THAN ('2010-01-01'))",
+			Output: "ALTER TABLE `test2` PARTITION BY RANGE COLUMNS (created_at) (PARTITION `p20100101` VALUES LESS THAN ('2010-01-01'))",
 			Do: func(partitions ...*Partition) (Handler, error) {
 				return r.PrepareCreates(partitions...)
 			},
@@ -30,7 +30,7 @@ func TestRangePartitioner(t *testing.T) {
 				NewPartition("p20110101", "2011-01-01", ""),
 				NewPartition("p20120101", "2012-01-01", ""),
 			},
-			Output: "ALTER TABLE test2 ADD PARTITION (PARTITION p20110101 VALUES LESS THAN ('2011-01-01'), PARTITION p20120101 VALUES LESS THAN ('2012-01-01'))",
+			Output: "ALTER TABLE `test2` ADD PARTITION (PARTITION `p20110101` VALUES LESS THAN ('2011-01-01'), PARTITION `p20120101` VALUES LESS THAN ('2012-01-01'))",
 			Do: func(partitions ...*Partition) (Handler, error) {
 				return r.PrepareAdds(partitions...)
 			},
@@ -52,8 +52,8 @@ func TestRangePartitioner(t *testing.T) {
 
 	t.Run("catch all", func(t *testing.T) {
 		p := NewPartition("p20100101", "TO_DAYS('2010-01-01')", "")
-		expect := "ALTER TABLE test3 PARTITION BY RANGE (TO_DAYS(created_at)) (PARTITION p20100101 VALUES LESS THAN (TO_DAYS('2010-01-01')), PARTITION pmax VALUES LESS THAN (MAXVALUE))"
-		r := NewRangePartitioner(nil, "test3", "TO_DAYS(created_at)", CatchAllPartitionName("pmax"))
+		expect := "ALTER TABLE `test3` PARTITION BY RANGE (TO_DAYS(created_at)) (PARTITION `p20100101` VALUES LESS THAN (TO_DAYS('2010-01-01')), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
+		r := NewRangePartitioner(nil, "test3", "TO_DAYS(created_at)", CatchAllPartitionName("pmax")).(*partitioner)
 		h, err := r.PrepareCreates(p)
 		if err != nil {
 			t.Fatal("error prepare creates.", err.Error())
@@ -62,6 +62,133 @@ func TestRangePartitioner(t *testing.T) {
 		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
 			t.Fatalf("error invalid result. %s", diff)
 		}
+
+		h, err = r.PrepareAddCatchAllPartition()
+		if err != nil {
+			t.Fatal("error prepare add catch all partition.", err.Error())
+		}
+
+		expect = "ALTER TABLE `test3` ADD PARTITION (PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result. %s", diff)
+		}
+
+		h, err = r.PrepareReorganizeCatchAllPartition(NewPartition("p20110101", "TO_DAYS('2011-01-01')", ""))
+		if err != nil {
+			t.Fatal("error prepare reorganize catch all partition.", err.Error())
+		}
+
+		expect = "ALTER TABLE `test3` REORGANIZE PARTITION `pmax` INTO (PARTITION `p20110101` VALUES LESS THAN (TO_DAYS('2011-01-01')), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result. %s", diff)
+		}
+	})
+
+	t.Run("reorganize", func(t *testing.T) {
+		from := []*Partition{NewPartition("pmax", CatchAllPartitionValue, "")}
+		into := []*Partition{
+			NewPartition("p20130101", "2013-01-01", ""),
+			NewPartition("pmax", CatchAllPartitionValue, ""),
+		}
+
+		h, err := r.(Reorganizer).PrepareReorganizes(from, into)
+		if err != nil {
+			t.Fatal("error prepare reorganizes.", err.Error())
+		}
+
+		expect := "ALTER TABLE `test2` REORGANIZE PARTITION `pmax` INTO (PARTITION `p20130101` VALUES LESS THAN ('2013-01-01'), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result. %s", diff)
+		}
+
+		from = []*Partition{
+			NewPartition("p20110101", "2011-01-01", ""),
+			NewPartition("p20120101", "2012-01-01", ""),
+		}
+		into = []*Partition{NewPartition("p20120101", "2012-01-01", "")}
+
+		h, err = r.(Reorganizer).PrepareReorganizes(from, into)
+		if err != nil {
+			t.Fatal("error prepare reorganizes.", err.Error())
+		}
+
+		expect = "ALTER TABLE `test2` REORGANIZE PARTITION `p20110101`,`p20120101` INTO (PARTITION `p20120101` VALUES LESS THAN ('2012-01-01'))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result. %s", diff)
+		}
+
+		into = []*Partition{NewPartition("p20130101", "2013-01-01", "")}
+		if _, err := r.(Reorganizer).PrepareReorganizes(from, into); err == nil {
+			t.Fatal("error changed range must be error.")
+		}
+
+		days := NewRangePartitioner(nil, "test3", "TO_DAYS(created_at)").(Reorganizer)
+		from = []*Partition{NewPartition("p20100101", "734138", "")}
+		into = []*Partition{
+			NewPartition("p20091201", "TO_DAYS('2009-12-01')", ""),
+			NewPartition("p20100101", "TO_DAYS('2010-01-01')", ""),
+		}
+		if _, err := days.PrepareReorganizes(from, into); err != nil {
+			t.Fatal("error prepare reorganizes.", err.Error())
+		}
+
+		into = []*Partition{NewPartition("p20100101", "734138 + 0", "")}
+		if _, err := days.PrepareReorganizes(from, into); err == nil {
+			t.Fatal("error incomparable range must be error.")
+		}
+	})
+
+	t.Run("subpartition", func(t *testing.T) {
+		r := NewRangePartitioner(nil, "audit", "TO_DAYS(created_at)", SubpartitionBy("hash", "tenant_id", 4), CatchAllPartitionName("pmax"))
+		h, err := r.PrepareCreates(NewPartition("p20100101", "TO_DAYS('2010-01-01')", ""))
+		if err != nil {
+			t.Fatal("error prepare creates.", err.Error())
+		}
+
+		expect := "ALTER TABLE `audit` PARTITION BY RANGE (TO_DAYS(created_at)) SUBPARTITION BY HASH (tenant_id) SUBPARTITIONS 4 (PARTITION `p20100101` VALUES LESS THAN (TO_DAYS('2010-01-01')), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result. %s", diff)
+		}
+	})
+
+	t.Run("explicit subpartition", func(t *testing.T) {
+		r := NewRangePartitioner(nil, "audit", "TO_DAYS(created_at)", SubpartitionBy("hash", "tenant_id", 0), CatchAllPartitionName("pmax"))
+		h, err := r.PrepareCreates(NewPartition("p20100101", "TO_DAYS('2010-01-01')", "", NewSubpartition("s0", ""), NewSubpartition("s1", "second")))
+		if err != nil {
+			t.Fatal("error prepare creates.", err.Error())
+		}
+
+		expect := "ALTER TABLE `audit` PARTITION BY RANGE (TO_DAYS(created_at)) SUBPARTITION BY HASH (tenant_id) (PARTITION `p20100101` VALUES LESS THAN (TO_DAYS('2010-01-01')) (SUBPARTITION `s0`, SUBPARTITION `s1` COMMENT = 'second'), PARTITION `pmax` VALUES LESS THAN (MAXVALUE) (SUBPARTITION `pmaxsp0`, SUBPARTITION `pmaxsp1`))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result. %s", diff)
+		}
+
+		h, err = r.PrepareAdds(NewPartition("p20110101", "TO_DAYS('2011-01-01')", "", NewSubpartition("s2", ""), NewSubpartition("s3", "")))
+		if err != nil {
+			t.Fatal("error prepare adds.", err.Error())
+		}
+
+		expect = "ALTER TABLE `audit` ADD PARTITION (PARTITION `p20110101` VALUES LESS THAN (TO_DAYS('2011-01-01')) (SUBPARTITION `s2`, SUBPARTITION `s3`))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result. %s", diff)
+		}
+
+		h, err = r.PrepareReorganizeCatchAllPartition(NewPartition("p20120101", "TO_DAYS('2012-01-01')", "", NewSubpartition("s4", ""), NewSubpartition("s5", "")))
+		if err != nil {
+			t.Fatal("error prepare reorganize catch all partition.", err.Error())
+		}
+
+		expect = "ALTER TABLE `audit` REORGANIZE PARTITION `pmax` INTO (PARTITION `p20120101` VALUES LESS THAN (TO_DAYS('2012-01-01')) (SUBPARTITION `s4`, SUBPARTITION `s5`), PARTITION `pmax` VALUES LESS THAN (MAXVALUE) (SUBPARTITION `pmaxsp0`, SUBPARTITION `pmaxsp1`))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result. %s", diff)
+		}
+
+		if _, err := r.PrepareCreates(
+			NewPartition("p20100101", "TO_DAYS('2010-01-01')", "", NewSubpartition("s0", "")),
+			NewPartition("p20110101", "TO_DAYS('2011-01-01')", ""),
+		); err == nil {
+			t.Fatal("error mismatched subpartitions must be error.")
+		}
 	})
 }
 
@@ -69,7 +196,7 @@ func Test_range_buildPart(t *testing.T) {
 	r := &Range{}
 
 	p := NewPartition("p111", "111", "test111")
-	expect := "PARTITION p111 VALUES LESS THAN (111) COMMENT = 'test111'"
+	expect := "PARTITION `p111` VALUES LESS THAN (111) COMMENT = 'test111'"
 
 	result, err := r.buildPart(p)
 	if err != nil {
@@ -87,9 +214,9 @@ func Test_range_buildCatchAllPart(t *testing.T) {
 		catchAllPartitionName: "pmax",
 	}
 
-	expect := "ALTER TABLE test3 ADD PARTITION (PARTITION pmax VALUES LESS THAN (MAXVALUE))"
+	expect := "ALTER TABLE `test3` ADD PARTITION (PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
 
-	result, err := r.buildCatchAllPart()
+	result, err := r.buildCatchAllPart(NewPartition(r.catchAllPartitionName, CatchAllPartitionValue, ""))
 	if err != nil {
 		t.Fatal("error build catcch all part.", err.Error())
 	}
@@ -0,0 +1,168 @@
+package partition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Repartitioner removes or rewrites partitioning of the table
+type Repartitioner interface {
+	RemovePartitioning() error
+	Repartition(...*Partition) error
+
+	RemovePartitioningContext(context.Context) error
+	RepartitionContext(context.Context, ...*Partition) error
+
+	PrepareRemovePartitioning() (Handler, error)
+	PrepareRepartition(...*Partition) (Handler, error)
+
+	PrepareRemovePartitioningContext(context.Context) (Handler, error)
+	PrepareRepartitionContext(context.Context, ...*Partition) (Handler, error)
+}
+
+func (p *partitioner) RemovePartitioning() error {
+	return p.RemovePartitioningContext(context.Background())
+}
+
+func (p *partitioner) RemovePartitioningContext(ctx context.Context) error {
+	h, err := p.PrepareRemovePartitioningContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error PrepareRemovePartitioning")
+	}
+	return h.ExecuteContext(ctx)
+}
+
+func (p *partitioner) PrepareRemovePartitioning() (Handler, error) {
+	return p.PrepareRemovePartitioningContext(context.Background())
+}
+
+// PrepareRemovePartitioningContext prepares to make the table not partitioned keeping its rows.
+// it does nothing when the table is not partitioned by any method.
+func (p *partitioner) PrepareRemovePartitioningContext(ctx context.Context) (Handler, error) {
+	if p.db != nil {
+		infos, err := p.PartitionsContext(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error Partitions")
+		}
+		if len(infos) == 0 {
+			return handlers{}, nil
+		}
+	}
+
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return nil, errors.Wrap(err, "error quoteTable")
+	}
+
+	return p.newHandler(fmt.Sprintf("ALTER TABLE %s REMOVE PARTITIONING", table), ""), nil
+}
+
+func (p *partitioner) Repartition(partitions ...*Partition) error {
+	return p.RepartitionContext(context.Background(), partitions...)
+}
+
+func (p *partitioner) RepartitionContext(ctx context.Context, partitions ...*Partition) error {
+	h, err := p.PrepareRepartitionContext(ctx, partitions...)
+	if err != nil {
+		return errors.Wrap(err, "error PrepareRepartition")
+	}
+	return h.ExecuteContext(ctx)
+}
+
+func (p *partitioner) PrepareRepartition(partitions ...*Partition) (Handler, error) {
+	return p.PrepareRepartitionContext(context.Background(), partitions...)
+}
+
+// PrepareRepartitionContext prepares to rewrite partitioning of the table with method and expression of the partitioner,
+// even if the table is already partitioned by another method.
+// it does nothing when the table is already partitioned as specified.
+func (p *partitioner) PrepareRepartitionContext(ctx context.Context, partitions ...*Partition) (Handler, error) {
+	infos := []*PartitionInfo{}
+	if p.db != nil {
+		var err error
+		if infos, err = p.PartitionsContext(ctx); err != nil {
+			return nil, errors.Wrap(err, "error Partitions")
+		}
+
+		same, err := p.samePartitioning(infos, partitions)
+		if err != nil {
+			return nil, errors.Wrap(err, "error samePartitioning")
+		}
+		if same {
+			return handlers{}, nil
+		}
+	}
+
+	h, err := p.PrepareCreatesContext(ctx, partitions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error PrepareCreates")
+	}
+
+	// previous partitioning can't be restored by REMOVE PARTITIONING
+	if created, ok := h.(*handler); ok && 0 < len(infos) {
+		created.down = ""
+	}
+
+	return h, nil
+}
+
+// samePartitioning reports whether current partitions are same as the partitioner and partitions
+func (p *partitioner) samePartitioning(infos []*PartitionInfo, partitions []*Partition) (bool, error) {
+	if len(infos) == 0 {
+		return false, nil
+	}
+
+	if infos[0].Method != p.partitionType || normalizeExpression(infos[0].Expression) != normalizeExpression(p.expression) {
+		return false, nil
+	}
+
+	var subMethod string
+	if 0 < len(infos[0].Subpartitions) {
+		subMethod = infos[0].Subpartitions[0].Method
+	}
+	if subMethod != p.subpartitionType || (0 < p.subpartitionCount && len(infos[0].Subpartitions) != p.subpartitionCount) {
+		return false, nil
+	}
+
+	if isNumbered(p.partBuilder) {
+		count, named, err := countPartitions(partitions)
+		if err != nil {
+			return false, err
+		}
+		if !named {
+			return count == len(infos), nil
+		}
+	}
+
+	partitions, err := p.resolvePartitions(partitions)
+	if err != nil {
+		return false, errors.Wrap(err, "error resolvePartitions")
+	}
+
+	if r, ok := p.partBuilder.(*Range); ok && r.catchAllPartitionName != "" {
+		partitions = append(partitions, p.catchAllPartition(r.catchAllPartitionName))
+	}
+
+	if len(partitions) != len(infos) {
+		return false, nil
+	}
+
+	plan := &Plan{current: map[string]*PartitionInfo{}, expression: infos[0].Expression}
+	for i, info := range infos {
+		plan.current[info.Name] = info
+		if partitions[i].Name != info.Name || plan.changed(partitions[i]) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// normalizeExpression normalizes expression stored in information_schema such as to_days(`created_at`)
+func normalizeExpression(expression string) string {
+	expression = strings.Replace(expression, "`", "", -1)
+	return strings.ToLower(strings.Join(strings.Fields(expression), ""))
+}
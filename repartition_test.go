@@ -0,0 +1,105 @@
+package partition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrepareRepartition(t *testing.T) {
+	p := NewRangePartitioner(nil, "events", "TO_DAYS(created_at)", CatchAllPartitionName("pmax")).(Repartitioner)
+
+	h, err := p.PrepareRemovePartitioning()
+	if err != nil {
+		t.Fatal("error prepare remove partitioning.", err.Error())
+	}
+
+	if diff := cmp.Diff(h.Statement(), "ALTER TABLE `events` REMOVE PARTITIONING"); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	h, err = p.PrepareRepartition(NewRangePartitionTime("p201001", time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal("error prepare repartition.", err.Error())
+	}
+
+	expect := "ALTER TABLE `events` PARTITION BY RANGE (TO_DAYS(created_at)) (PARTITION `p201001` VALUES LESS THAN (TO_DAYS('2010-01-01')), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+}
+
+func Test_samePartitioning(t *testing.T) {
+	type Test struct {
+		Title       string
+		Partitioner Partitioner
+		Infos       []*PartitionInfo
+		Partitions  []*Partition
+		Same        bool
+	}
+
+	ranges := []*PartitionInfo{
+		&PartitionInfo{Name: "p201001", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "734138"},
+		&PartitionInfo{Name: "pmax", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "MAXVALUE"},
+	}
+
+	rangePartitioner := NewRangePartitioner(nil, "events", "TO_DAYS(created_at)", CatchAllPartitionName("pmax"))
+	p201001 := NewRangePartitionTime("p201001", time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []Test{
+		Test{
+			Title:       "not partitioned",
+			Partitioner: rangePartitioner,
+			Partitions:  []*Partition{p201001},
+		},
+		Test{
+			Title:       "same",
+			Partitioner: rangePartitioner,
+			Infos:       ranges,
+			Partitions:  []*Partition{p201001},
+			Same:        true,
+		},
+		Test{
+			Title:       "different boundary",
+			Partitioner: rangePartitioner,
+			Infos:       ranges,
+			Partitions:  []*Partition{NewRangePartitionTime("p201001", time.Date(2010, 2, 1, 0, 0, 0, 0, time.UTC))},
+		},
+		Test{
+			Title:       "different method",
+			Partitioner: NewListPartitioner(nil, "events", "TO_DAYS(created_at)"),
+			Infos:       ranges,
+			Partitions:  []*Partition{NewPartition("p201001", "734138", ""), NewPartition("pmax", "MAXVALUE", "")},
+		},
+		Test{
+			Title:       "different expression",
+			Partitioner: NewRangePartitioner(nil, "events", "TO_DAYS(updated_at)", CatchAllPartitionName("pmax")),
+			Infos:       ranges,
+			Partitions:  []*Partition{p201001},
+		},
+		Test{
+			Title:       "same number of hash partitions",
+			Partitioner: NewHashPartitioner(nil, "events", "user_id"),
+			Infos: []*PartitionInfo{
+				&PartitionInfo{Name: "p0", Method: "HASH", Expression: "`user_id`"},
+				&PartitionInfo{Name: "p1", Method: "HASH", Expression: "`user_id`"},
+			},
+			Partitions: NewPartitions(2),
+			Same:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			same, err := test.Partitioner.(*partitioner).samePartitioning(test.Infos, test.Partitions)
+			if err != nil {
+				t.Fatal("error same partitioning.", err.Error())
+			}
+
+			if same != test.Same {
+				t.Fatalf("error invalid result. got:%v want:%v", same, test.Same)
+			}
+		})
+	}
+}
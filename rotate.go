@@ -0,0 +1,302 @@
+package partition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Interval is unit of time based partition rotation
+type Interval int
+
+const (
+	// Hourly rotates partition every hour
+	Hourly Interval = iota + 1
+	// Daily rotates partition every day
+	Daily
+	// Weekly rotates partition every week. week starts on monday
+	Weekly
+	// Monthly rotates partition every month
+	Monthly
+	// Yearly rotates partition every year
+	Yearly
+)
+
+var intervalNames = map[Interval]string{
+	Hourly:  "hourly",
+	Daily:   "daily",
+	Weekly:  "weekly",
+	Monthly: "monthly",
+	Yearly:  "yearly",
+}
+
+// ParseInterval parse interval name such as daily or monthly
+func ParseInterval(name string) (Interval, error) {
+	for i, n := range intervalNames {
+		if n == strings.ToLower(name) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("error unknown interval %s", name)
+}
+
+func (i Interval) String() string {
+	if name, ok := intervalNames[i]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Interval(%d)", int(i))
+}
+
+// truncate returns start of the interval which t belongs to
+func (i Interval) truncate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch i {
+	case Hourly:
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location())
+	case Daily:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	case Weekly:
+		return time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	case Monthly:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	case Yearly:
+		return time.Date(y, 1, 1, 0, 0, 0, 0, t.Location())
+	}
+
+	return t
+}
+
+// add returns t moved n intervals. t must be truncated
+func (i Interval) add(t time.Time, n int) time.Time {
+	switch i {
+	case Hourly:
+		y, m, d := t.Date()
+		return time.Date(y, m, d, t.Hour()+n, 0, 0, 0, t.Location())
+	case Daily:
+		return t.AddDate(0, 0, n)
+	case Weekly:
+		return t.AddDate(0, 0, 7*n)
+	case Monthly:
+		return t.AddDate(0, n, 0)
+	case Yearly:
+		return t.AddDate(n, 0, 0)
+	}
+
+	return t
+}
+
+func (i Interval) defaultNameLayout() string {
+	switch i {
+	case Hourly:
+		return "p2006010215"
+	case Monthly:
+		return "p200601"
+	case Yearly:
+		return "p2006"
+	}
+
+	return "p20060102"
+}
+
+// Rotator plans adding future partitions and dropping expired partitions of time based range partition.
+// A partition is named after start of its interval and holds values less than start of next interval.
+type Rotator struct {
+	partitioner rotatePartitioner
+	interval    Interval
+	nameLayout  string
+	boundary    func(time.Time) string
+	lookAhead   int
+	retention   int
+	now         func() time.Time
+}
+
+// rotatePartitioner is partitioner which can be rotated.
+// range partitioners returned by NewRangePartitioner implement it.
+type rotatePartitioner interface {
+	ContextPartitioner
+	Inspector
+	checkTimeValue() error
+	now() time.Time
+}
+
+// RotateOption use new rotator
+type RotateOption func(*Rotator)
+
+// NameLayout set time layout of partition name. default is like p20060102 depending on interval.
+func NameLayout(layout string) RotateOption {
+	return func(r *Rotator) {
+		r.nameLayout = layout
+	}
+}
+
+// BoundaryFormat set formatter of partition description.
+// By default boundaries are written as time values, which are quoted for RANGE COLUMNS partition
+// and wrapped with the function of expression such as TO_DAYS for RANGE partition.
+func BoundaryFormat(format func(time.Time) string) RotateOption {
+	return func(r *Rotator) {
+		r.boundary = format
+	}
+}
+
+// LookAhead set number of future intervals to be prepared in addition to current interval. default 1
+func LookAhead(n int) RotateOption {
+	return func(r *Rotator) {
+		r.lookAhead = n
+	}
+}
+
+// Retention set number of past intervals to be kept in addition to current interval.
+// default 0 means partitions are never dropped.
+func Retention(n int) RotateOption {
+	return func(r *Rotator) {
+		r.retention = n
+	}
+}
+
+// NewRotator is XXX
+func NewRotator(p RangePartitioner, interval Interval, options ...RotateOption) *Rotator {
+	rotated, _ := p.(rotatePartitioner)
+	r := &Rotator{
+		partitioner: rotated,
+		interval:    interval,
+		nameLayout:  interval.defaultNameLayout(),
+		lookAhead:   1,
+		now:         time.Now,
+	}
+	if rotated != nil {
+		r.now = rotated.now
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Rotate adds and drops partitions
+func (r *Rotator) Rotate() error {
+	return r.RotateContext(context.Background())
+}
+
+// RotateContext is Rotate with context
+func (r *Rotator) RotateContext(ctx context.Context) error {
+	h, err := r.PrepareContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error Prepare")
+	}
+	return h.ExecuteContext(ctx)
+}
+
+// Prepare returns handler which adds missing partitions and drops expired partitions.
+// Statement of the handler is empty when nothing to do.
+func (r *Rotator) Prepare() (Handler, error) {
+	return r.PrepareContext(context.Background())
+}
+
+// PrepareContext is Prepare with context
+func (r *Rotator) PrepareContext(ctx context.Context) (Handler, error) {
+	if _, ok := intervalNames[r.interval]; !ok {
+		return nil, fmt.Errorf("error invalid interval %s", r.interval)
+	}
+
+	if r.lookAhead < 0 || r.retention < 0 {
+		return nil, fmt.Errorf("error look ahead and retention must not be negative")
+	}
+
+	if r.partitioner == nil {
+		return nil, fmt.Errorf("error partitioner doesn't support rotation")
+	}
+
+	if r.boundary == nil {
+		if err := r.partitioner.checkTimeValue(); err != nil {
+			return nil, errors.Wrap(err, "error boundary of partition")
+		}
+	}
+
+	now := r.interval.truncate(r.now())
+
+	partitioned, err := r.partitioner.IsPartitionedContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error IsPartitioned")
+	}
+
+	if !partitioned {
+		h, err := r.partitioner.PrepareCreatesContext(ctx, r.partitions(now, now)...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error PrepareCreates")
+		}
+		return handlers{h}, nil
+	}
+
+	infos, err := r.partitioner.PartitionsContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error Partitions")
+	}
+
+	var latest time.Time
+	expired := []*Partition{}
+	expire := r.interval.add(now, -r.retention)
+	for _, info := range infos {
+		start, err := time.ParseInLocation(r.nameLayout, info.Name, now.Location())
+		if err != nil {
+			// not managed by rotator. e.g. catch all partition
+			continue
+		}
+
+		if latest.IsZero() || start.After(latest) {
+			latest = start
+		}
+
+		if 0 < r.retention && !r.interval.add(start, 1).After(expire) {
+			expired = append(expired, NewPartition(info.Name, "", ""))
+		}
+	}
+
+	// fill missing intervals after latest partition
+	from := now
+	if !latest.IsZero() {
+		from = r.interval.add(latest, 1)
+	}
+
+	hs := handlers{}
+	if adds := r.partitions(from, now); 0 < len(adds) {
+		h, err := r.partitioner.PrepareAddsContext(ctx, adds...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error PrepareAdds")
+		}
+		hs = append(hs, h)
+	}
+
+	if 0 < len(expired) {
+		h, err := r.partitioner.PrepareDropsContext(ctx, expired...)
+		if err != nil {
+			return nil, errors.Wrap(err, "error PrepareDrops")
+		}
+		hs = append(hs, h)
+	}
+
+	return hs, nil
+}
+
+// partitions returns partitions from the interval until look ahead of now
+func (r *Rotator) partitions(from, now time.Time) []*Partition {
+	last := r.interval.add(now, r.lookAhead)
+
+	partitions := []*Partition{}
+	for start := from; !start.After(last); start = r.interval.add(start, 1) {
+		name, end := start.Format(r.nameLayout), r.interval.add(start, 1)
+		if r.boundary == nil {
+			partitions = append(partitions, NewRangePartitionTime(name, end))
+			continue
+		}
+		partitions = append(partitions, NewPartition(name, r.boundary(end), ""))
+	}
+
+	return partitions
+}
@@ -0,0 +1,178 @@
+package partition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type stubPartitioner struct {
+	*partitioner
+	infos []*PartitionInfo
+}
+
+func (p *stubPartitioner) IsPartitionedContext(ctx context.Context) (bool, error) {
+	return 0 < len(p.infos), nil
+}
+
+func (p *stubPartitioner) PartitionsContext(ctx context.Context) ([]*PartitionInfo, error) {
+	return p.infos, nil
+}
+
+func TestRotator(t *testing.T) {
+	now := func() time.Time {
+		return time.Date(2024, 3, 15, 12, 30, 0, 0, time.UTC)
+	}
+
+	type Test struct {
+		Title       string
+		Partitioner RangePartitioner
+		Infos       []string
+		Rotator     func(RangePartitioner) *Rotator
+		Output      string
+	}
+
+	tests := []Test{
+		Test{
+			Title: "create monthly partitions",
+			Rotator: func(p RangePartitioner) *Rotator {
+				return NewRotator(p, Monthly, LookAhead(2))
+			},
+			Output: "ALTER TABLE `events` PARTITION BY RANGE COLUMNS (created_at) (PARTITION `p202403` VALUES LESS THAN ('2024-04-01'), PARTITION `p202404` VALUES LESS THAN ('2024-05-01'), PARTITION `p202405` VALUES LESS THAN ('2024-06-01'))",
+		},
+		Test{
+			Title: "add and drop monthly partitions",
+			Infos: []string{"p202312", "p202401", "p202402", "p202403"},
+			Rotator: func(p RangePartitioner) *Rotator {
+				return NewRotator(p, Monthly, Retention(2))
+			},
+			Output: "ALTER TABLE `events` ADD PARTITION (PARTITION `p202404` VALUES LESS THAN ('2024-05-01'));\nALTER TABLE `events` DROP PARTITION `p202312`",
+		},
+		Test{
+			Title: "nothing to do",
+			Infos: []string{"p202403", "p202404", "pmax"},
+			Rotator: func(p RangePartitioner) *Rotator {
+				return NewRotator(p, Monthly)
+			},
+			Output: "",
+		},
+		Test{
+			Title: "daily partitions with custom layout",
+			Infos: []string{"d20240314"},
+			Rotator: func(p RangePartitioner) *Rotator {
+				return NewRotator(p, Daily, NameLayout("d20060102"), Retention(1), BoundaryFormat(func(t time.Time) string {
+					return t.Format("TO_DAYS('2006-01-02')")
+				}))
+			},
+			Output: "ALTER TABLE `events` ADD PARTITION (PARTITION `d20240315` VALUES LESS THAN (TO_DAYS('2024-03-16')), PARTITION `d20240316` VALUES LESS THAN (TO_DAYS('2024-03-17')))",
+		},
+		Test{
+			Title:       "monthly partitions by function",
+			Partitioner: NewRangePartitioner(nil, "events", "TO_DAYS(created_at)"),
+			Infos:       []string{"p202402", "p202403"},
+			Rotator: func(p RangePartitioner) *Rotator {
+				return NewRotator(p, Monthly)
+			},
+			Output: "ALTER TABLE `events` ADD PARTITION (PARTITION `p202404` VALUES LESS THAN (TO_DAYS('2024-05-01')))",
+		},
+		Test{
+			Title:       "yearly partitions by unix timestamp",
+			Partitioner: NewRangePartitioner(nil, "events", "UNIX_TIMESTAMP(created_at)"),
+			Rotator: func(p RangePartitioner) *Rotator {
+				return NewRotator(p, Yearly, LookAhead(0))
+			},
+			Output: "ALTER TABLE `events` PARTITION BY RANGE (UNIX_TIMESTAMP(created_at)) (PARTITION `p2024` VALUES LESS THAN (UNIX_TIMESTAMP('2025-01-01')))",
+		},
+		Test{
+			Title: "weekly partitions",
+			Rotator: func(p RangePartitioner) *Rotator {
+				return NewRotator(p, Weekly, LookAhead(0))
+			},
+			Output: "ALTER TABLE `events` PARTITION BY RANGE COLUMNS (created_at) (PARTITION `p20240311` VALUES LESS THAN ('2024-03-18'))",
+		},
+		Test{
+			Title: "hourly partitions",
+			Rotator: func(p RangePartitioner) *Rotator {
+				return NewRotator(p, Hourly)
+			},
+			Output: "ALTER TABLE `events` PARTITION BY RANGE COLUMNS (created_at) (PARTITION `p2024031512` VALUES LESS THAN ('2024-03-15 13:00:00'), PARTITION `p2024031513` VALUES LESS THAN ('2024-03-15 14:00:00'))",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			rng := test.Partitioner
+			if rng == nil {
+				rng = NewRangePartitioner(nil, "events", "created_at", Type("range columns"))
+			}
+
+			p := &stubPartitioner{
+				partitioner: rng.(*partitioner),
+			}
+			for _, name := range test.Infos {
+				p.infos = append(p.infos, &PartitionInfo{Name: name})
+			}
+
+			r := test.Rotator(p)
+			r.now = now
+
+			h, err := r.Prepare()
+			if err != nil {
+				t.Fatal("error prepare.", err.Error())
+			}
+
+			if diff := cmp.Diff(h.Statement(), test.Output); diff != "" {
+				t.Fatalf("error invalid result:%s", diff)
+			}
+		})
+	}
+
+	t.Run("invalid interval", func(t *testing.T) {
+		r := NewRotator(&stubPartitioner{}, Interval(0))
+		if _, err := r.Prepare(); err == nil {
+			t.Fatal("error invalid interval must be error.")
+		}
+	})
+
+	t.Run("unsupported expression", func(t *testing.T) {
+		p := &stubPartitioner{
+			partitioner: NewRangePartitioner(nil, "events", "id").(*partitioner),
+		}
+
+		if _, err := NewRotator(p, Monthly).Prepare(); err == nil {
+			t.Fatal("error time value of expression without function must be error.")
+		}
+
+		r := NewRotator(p, Monthly, BoundaryFormat(func(t time.Time) string {
+			return t.Format("20060102")
+		}))
+		r.now = now
+
+		h, err := r.Prepare()
+		if err != nil {
+			t.Fatal("error prepare.", err.Error())
+		}
+
+		expect := "ALTER TABLE `events` PARTITION BY RANGE (id) (PARTITION `p202403` VALUES LESS THAN (20240401), PARTITION `p202404` VALUES LESS THAN (20240501))"
+		if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+			t.Fatalf("error invalid result:%s", diff)
+		}
+	})
+}
+
+func TestParseInterval(t *testing.T) {
+	i, err := ParseInterval("Monthly")
+	if err != nil {
+		t.Fatal("error parse interval.", err.Error())
+	}
+
+	if i != Monthly {
+		t.Fatalf("error invalid result. got:%s want:%s", i, Monthly)
+	}
+
+	if _, err := ParseInterval("minutely"); err == nil {
+		t.Fatal("error unknown interval must be error.")
+	}
+}
@@ -0,0 +1,453 @@
+package partition
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ShowCreateTable reads partitions from SHOW CREATE TABLE instead of information_schema.
+// It is for environments where information_schema is restricted. sizes and times of PartitionInfo are not available.
+func ShowCreateTable(use bool) Option {
+	return func(p *partitioner) {
+		p.showCreateTable = use
+	}
+}
+
+// partitionsFromShowCreateTable returns partitions parsed from SHOW CREATE TABLE
+func (p *partitioner) partitionsFromShowCreateTable(ctx context.Context) ([]*PartitionInfo, error) {
+	table, err := quoteTable(p.qualifiedTable())
+	if err != nil {
+		return nil, errors.Wrap(err, "error quoteTable")
+	}
+
+	var name, statement sql.NullString
+	if err := p.db.QueryRowContext(ctx, fmt.Sprintf("SHOW CREATE TABLE %s", table)).Scan(&name, &statement); err != nil {
+		return nil, errors.Wrap(err, "error show create table")
+	}
+
+	infos, err := ParseCreateTable(statement.String)
+	if err != nil {
+		return nil, errors.Wrap(err, "error ParseCreateTable")
+	}
+
+	return infos, nil
+}
+
+// ParseCreateTable returns partitions of PARTITION BY clause in CREATE TABLE statement such as result of SHOW CREATE TABLE.
+// Method, Expression and Description are written as SHOW CREATE TABLE does, so they may differ from information_schema in spacing and quoting.
+// It returns no partitions when the table is not partitioned.
+func ParseCreateTable(statement string) ([]*PartitionInfo, error) {
+	tokens, err := tokenize(statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "error tokenize")
+	}
+
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].is("PARTITION") && tokens[i+1].is("BY") {
+			c := &clauseParser{source: statement, tokens: tokens, pos: i}
+			infos, err := c.parse()
+			if err != nil {
+				return nil, errors.Wrap(err, "error parse partition clause")
+			}
+			return infos, nil
+		}
+	}
+
+	return []*PartitionInfo{}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenSymbol
+)
+
+type token struct {
+	kind       tokenKind
+	text       string
+	start, end int
+}
+
+// is reports whether token is the keyword or symbol
+func (t *token) is(s string) bool {
+	return (t.kind == tokenWord || t.kind == tokenSymbol) && strings.EqualFold(t.text, s)
+}
+
+// tokenize splits statement into words, quoted identifiers, strings and symbols.
+// version comments such as /*!50100 and */ are skipped and their contents are tokenized.
+func tokenize(statement string) ([]*token, error) {
+	tokens := []*token{}
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(statement[i:], "/*!"):
+			i += 3
+			for i < len(statement) && '0' <= statement[i] && statement[i] <= '9' {
+				i++
+			}
+		case strings.HasPrefix(statement[i:], "*/"):
+			i += 2
+		case c == '\'' || c == '"' || c == '`':
+			end, err := quoteEnd(statement, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokenString
+			if c == '`' {
+				kind = tokenIdentifier
+			}
+			tokens = append(tokens, &token{kind: kind, text: statement[i:end], start: i, end: end})
+			i = end
+		case isWordByte(c):
+			end := i
+			for end < len(statement) && isWordByte(statement[end]) {
+				end++
+			}
+			tokens = append(tokens, &token{kind: tokenWord, text: statement[i:end], start: i, end: end})
+			i = end
+		default:
+			tokens = append(tokens, &token{kind: tokenSymbol, text: statement[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c == '-' || c == '+' || '0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || 0x80 <= c
+}
+
+// quoteEnd returns position next to the closing quote
+func quoteEnd(statement string, start int) (int, error) {
+	quote := statement[start]
+	for i := start + 1; i < len(statement); i++ {
+		switch {
+		case statement[i] == '\\' && quote != '`':
+			i++
+		case statement[i] == quote && i+1 < len(statement) && statement[i+1] == quote:
+			i++
+		case statement[i] == quote:
+			return i + 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("error unclosed quote at %d", start)
+}
+
+type clauseParser struct {
+	source string
+	tokens []*token
+	pos    int
+}
+
+func (c *clauseParser) peek() *token {
+	if c.pos < len(c.tokens) {
+		return c.tokens[c.pos]
+	}
+
+	return &token{kind: tokenSymbol}
+}
+
+func (c *clauseParser) accept(s string) bool {
+	if c.peek().is(s) {
+		c.pos++
+		return true
+	}
+
+	return false
+}
+
+func (c *clauseParser) expect(s string) error {
+	if !c.accept(s) {
+		return fmt.Errorf("error %s is expected but %q is found", s, c.peek().text)
+	}
+
+	return nil
+}
+
+// name returns identifier with backtick unquoted
+func (c *clauseParser) name() (string, error) {
+	t := c.peek()
+	switch t.kind {
+	case tokenWord:
+		c.pos++
+		return t.text, nil
+	case tokenIdentifier:
+		c.pos++
+		return strings.Replace(t.text[1:len(t.text)-1], "``", "`", -1), nil
+	}
+
+	return "", fmt.Errorf("error name is expected but %q is found", t.text)
+}
+
+func (c *clauseParser) number() (int, error) {
+	t := c.peek()
+	n, err := strconv.Atoi(t.text)
+	if err != nil || t.kind != tokenWord {
+		return 0, fmt.Errorf("error number is expected but %q is found", t.text)
+	}
+	c.pos++
+
+	return n, nil
+}
+
+// bracket returns text inside of brackets as written in statement
+func (c *clauseParser) bracket() (string, error) {
+	if err := c.expect("("); err != nil {
+		return "", err
+	}
+
+	start := c.pos
+	for depth := 1; c.pos < len(c.tokens); c.pos++ {
+		switch {
+		case c.peek().is("("):
+			depth++
+		case c.peek().is(")"):
+			depth--
+		}
+		if depth == 0 {
+			text := ""
+			if start < c.pos {
+				text = c.source[c.tokens[start].start:c.tokens[c.pos-1].end]
+			}
+			c.pos++
+			return strings.TrimSpace(text), nil
+		}
+	}
+
+	return "", fmt.Errorf("error unclosed bracket")
+}
+
+// method parses such as LINEAR KEY ALGORITHM = 2 (id) or RANGE COLUMNS(a, b)
+func (c *clauseParser) method() (string, string, error) {
+	words := []string{}
+	if c.accept("LINEAR") {
+		words = append(words, "LINEAR")
+	}
+
+	t := c.peek()
+	if !t.is(PartitionTypeRange) && !t.is(PartitionTypeList) && !t.is(PartitionTypeHash) && !t.is(PartitionTypeKey) {
+		return "", "", fmt.Errorf("error unknown partition method %q", t.text)
+	}
+	c.pos++
+	words = append(words, strings.ToUpper(t.text))
+
+	if c.accept("ALGORITHM") {
+		if err := c.expect("="); err != nil {
+			return "", "", err
+		}
+		if _, err := c.number(); err != nil {
+			return "", "", err
+		}
+	}
+
+	if c.accept("COLUMNS") {
+		words = append(words, "COLUMNS")
+	}
+
+	expression, err := c.bracket()
+	if err != nil {
+		return "", "", err
+	}
+
+	return strings.Join(words, " "), expression, nil
+}
+
+func (c *clauseParser) parse() ([]*PartitionInfo, error) {
+	if err := c.expect("PARTITION"); err != nil {
+		return nil, err
+	}
+	if err := c.expect("BY"); err != nil {
+		return nil, err
+	}
+
+	method, expression, err := c.method()
+	if err != nil {
+		return nil, errors.Wrap(err, "error partition method")
+	}
+
+	count := 0
+	if c.accept("PARTITIONS") {
+		if count, err = c.number(); err != nil {
+			return nil, err
+		}
+	}
+
+	var subMethod, subExpression string
+	subCount := 0
+	if c.accept("SUBPARTITION") {
+		if err := c.expect("BY"); err != nil {
+			return nil, err
+		}
+		if subMethod, subExpression, err = c.method(); err != nil {
+			return nil, errors.Wrap(err, "error subpartition method")
+		}
+		if c.accept("SUBPARTITIONS") {
+			if subCount, err = c.number(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	infos := []*PartitionInfo{}
+	if c.accept("(") {
+		for {
+			info, err := c.partition()
+			if err != nil {
+				return nil, errors.Wrapf(err, "error partition at %d", len(infos)+1)
+			}
+			infos = append(infos, info)
+
+			if c.accept(")") {
+				break
+			}
+			if err := c.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		// partitions are named p0, p1 ... when they are not defined
+		for i := 0; i < count; i++ {
+			infos = append(infos, &PartitionInfo{Name: fmt.Sprintf("p%d", i)})
+		}
+	}
+
+	for i, info := range infos {
+		info.OrdinalPosition = i + 1
+		info.Method = method
+		info.Expression = expression
+
+		if subMethod == "" {
+			continue
+		}
+
+		// subpartitions are named PARTITIONsp0, PARTITIONsp1 ... when they are not defined
+		if len(info.Subpartitions) == 0 {
+			for j := 0; j < subCount; j++ {
+				info.Subpartitions = append(info.Subpartitions, &SubpartitionInfo{Name: fmt.Sprintf("%ssp%d", info.Name, j)})
+			}
+		}
+		for j, sub := range info.Subpartitions {
+			sub.OrdinalPosition = j + 1
+			sub.Method = subMethod
+			sub.Expression = subExpression
+		}
+	}
+
+	return infos, nil
+}
+
+// partition parses such as PARTITION p0 VALUES LESS THAN (10) COMMENT = 'c' ENGINE = InnoDB
+func (c *clauseParser) partition() (*PartitionInfo, error) {
+	if err := c.expect("PARTITION"); err != nil {
+		return nil, err
+	}
+
+	name, err := c.name()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PartitionInfo{Name: name}
+	if c.accept("VALUES") {
+		switch {
+		case c.accept("LESS"):
+			if err := c.expect("THAN"); err != nil {
+				return nil, err
+			}
+			if c.accept(CatchAllPartitionValue) {
+				info.Description = CatchAllPartitionValue
+			} else if info.Description, err = c.bracket(); err != nil {
+				return nil, err
+			}
+		case c.accept("IN"):
+			if info.Description, err = c.bracket(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("error LESS THAN or IN is expected but %q is found", c.peek().text)
+		}
+	}
+
+	if info.Comment, info.Engine, err = c.options(); err != nil {
+		return nil, errors.Wrapf(err, "error options of partition %s", name)
+	}
+
+	if c.accept("(") {
+		for {
+			if err := c.expect("SUBPARTITION"); err != nil {
+				return nil, err
+			}
+
+			subName, err := c.name()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, _, err := c.options(); err != nil {
+				return nil, errors.Wrapf(err, "error options of subpartition %s", subName)
+			}
+			info.Subpartitions = append(info.Subpartitions, &SubpartitionInfo{Name: subName})
+
+			if c.accept(")") {
+				break
+			}
+			if err := c.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// options parses partition options and returns comment and engine.
+// other options such as DATA DIRECTORY and MAX_ROWS are skipped.
+func (c *clauseParser) options() (string, string, error) {
+	var comment, engine string
+	for {
+		t := c.peek()
+		if t.kind != tokenWord {
+			return comment, engine, nil
+		}
+		c.pos++
+
+		switch {
+		case t.is("STORAGE"), t.is("DATA"), t.is("INDEX"):
+			continue
+		case t.is("ENGINE"):
+			c.accept("=")
+			name, err := c.name()
+			if err != nil {
+				return "", "", err
+			}
+			engine = name
+		case t.is("COMMENT"):
+			c.accept("=")
+			value := c.peek()
+			if value.kind != tokenString {
+				return "", "", fmt.Errorf("error comment string is expected but %q is found", value.text)
+			}
+			c.pos++
+			text, ok := unquoteString(value.text)
+			if !ok {
+				return "", "", fmt.Errorf("error invalid comment %s", value.text)
+			}
+			comment = text
+		default:
+			c.accept("=")
+			c.pos++
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package partition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCreateTable(t *testing.T) {
+	type Test struct {
+		Title  string
+		Input  string
+		Output []*PartitionInfo
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "not partitioned",
+			Input:  "CREATE TABLE `test` (\n  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8",
+			Output: []*PartitionInfo{},
+		},
+		Test{
+			Title: "list",
+			Input: "CREATE TABLE `test` (\n  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,\n  `event_id` int(11) NOT NULL,\n  PRIMARY KEY (`id`,`event_id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8\n" +
+				"/*!50100 PARTITION BY LIST (event_id)\n" +
+				"(PARTITION e00001 VALUES IN (1) COMMENT = 'event_id = 1' ENGINE = InnoDB,\n" +
+				" PARTITION e00002 VALUES IN (2) COMMENT = 'event_id = 2' ENGINE = InnoDB,\n" +
+				" PARTITION e00003 VALUES IN (3,4) COMMENT = 'event_id = 3 and 4' ENGINE = InnoDB) */",
+			Output: []*PartitionInfo{
+				&PartitionInfo{Name: "e00001", OrdinalPosition: 1, Method: "LIST", Expression: "event_id", Description: "1", Comment: "event_id = 1", Engine: "InnoDB"},
+				&PartitionInfo{Name: "e00002", OrdinalPosition: 2, Method: "LIST", Expression: "event_id", Description: "2", Comment: "event_id = 2", Engine: "InnoDB"},
+				&PartitionInfo{Name: "e00003", OrdinalPosition: 3, Method: "LIST", Expression: "event_id", Description: "3,4", Comment: "event_id = 3 and 4", Engine: "InnoDB"},
+			},
+		},
+		Test{
+			Title: "range columns",
+			Input: "CREATE TABLE `logs` (\n  `created_at` datetime NOT NULL\n) ENGINE=InnoDB\n" +
+				"/*!50500 PARTITION BY RANGE  COLUMNS(created_at)\n" +
+				"(PARTITION p202401 VALUES LESS THAN ('2024-02-01') COMMENT = 'it''s january' ENGINE = InnoDB,\n" +
+				" PARTITION `p max` VALUES LESS THAN (MAXVALUE) ENGINE = InnoDB) */",
+			Output: []*PartitionInfo{
+				&PartitionInfo{Name: "p202401", OrdinalPosition: 1, Method: "RANGE COLUMNS", Expression: "created_at", Description: "'2024-02-01'", Comment: "it's january", Engine: "InnoDB"},
+				&PartitionInfo{Name: "p max", OrdinalPosition: 2, Method: "RANGE COLUMNS", Expression: "created_at", Description: "MAXVALUE", Engine: "InnoDB"},
+			},
+		},
+		Test{
+			Title: "range subpartitions",
+			Input: "CREATE TABLE `audit` (\n  `created_at` datetime NOT NULL\n) ENGINE=InnoDB\n" +
+				"/*!50100 PARTITION BY RANGE (to_days(`created_at`))\n" +
+				"SUBPARTITION BY HASH (tenant_id)\n" +
+				"SUBPARTITIONS 2\n" +
+				"(PARTITION p0 VALUES LESS THAN (733042) ENGINE = InnoDB,\n" +
+				" PARTITION pmax VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */",
+			Output: []*PartitionInfo{
+				&PartitionInfo{Name: "p0", OrdinalPosition: 1, Method: "RANGE", Expression: "to_days(`created_at`)", Description: "733042", Engine: "InnoDB",
+					Subpartitions: []*SubpartitionInfo{
+						&SubpartitionInfo{Name: "p0sp0", OrdinalPosition: 1, Method: "HASH", Expression: "tenant_id"},
+						&SubpartitionInfo{Name: "p0sp1", OrdinalPosition: 2, Method: "HASH", Expression: "tenant_id"},
+					},
+				},
+				&PartitionInfo{Name: "pmax", OrdinalPosition: 2, Method: "RANGE", Expression: "to_days(`created_at`)", Description: "MAXVALUE", Engine: "InnoDB",
+					Subpartitions: []*SubpartitionInfo{
+						&SubpartitionInfo{Name: "pmaxsp0", OrdinalPosition: 1, Method: "HASH", Expression: "tenant_id"},
+						&SubpartitionInfo{Name: "pmaxsp1", OrdinalPosition: 2, Method: "HASH", Expression: "tenant_id"},
+					},
+				},
+			},
+		},
+		Test{
+			Title: "explicit subpartitions",
+			Input: "/*!50100 PARTITION BY LIST COLUMNS(country,region)\n" +
+				"SUBPARTITION BY LINEAR KEY ALGORITHM = 2 (id)\n" +
+				"(PARTITION jp VALUES IN (('jp','east'),('jp','west'))\n" +
+				" (SUBPARTITION s0 COMMENT = 'first' DATA DIRECTORY = '/data' ENGINE = InnoDB,\n" +
+				"  SUBPARTITION s1 ENGINE = InnoDB)) */",
+			Output: []*PartitionInfo{
+				&PartitionInfo{Name: "jp", OrdinalPosition: 1, Method: "LIST COLUMNS", Expression: "country,region", Description: "('jp','east'),('jp','west')",
+					Subpartitions: []*SubpartitionInfo{
+						&SubpartitionInfo{Name: "s0", OrdinalPosition: 1, Method: "LINEAR KEY", Expression: "id"},
+						&SubpartitionInfo{Name: "s1", OrdinalPosition: 2, Method: "LINEAR KEY", Expression: "id"},
+					},
+				},
+			},
+		},
+		Test{
+			Title: "hash",
+			Input: "CREATE TABLE `test6` (\n  `user_id` int(11) NOT NULL\n) ENGINE=InnoDB\n/*!50100 PARTITION BY HASH (user_id)\nPARTITIONS 2 */",
+			Output: []*PartitionInfo{
+				&PartitionInfo{Name: "p0", OrdinalPosition: 1, Method: "HASH", Expression: "user_id"},
+				&PartitionInfo{Name: "p1", OrdinalPosition: 2, Method: "HASH", Expression: "user_id"},
+			},
+		},
+		Test{
+			Title: "mysql 8.0",
+			Input: "CREATE TABLE `test` (\n  `id` int NOT NULL\n) ENGINE=InnoDB\n/*!50100 PARTITION BY KEY (`id`)\n(PARTITION a ENGINE = InnoDB,\n PARTITION b ENGINE = InnoDB) */",
+			Output: []*PartitionInfo{
+				&PartitionInfo{Name: "a", OrdinalPosition: 1, Method: "KEY", Expression: "`id`", Engine: "InnoDB"},
+				&PartitionInfo{Name: "b", OrdinalPosition: 2, Method: "KEY", Expression: "`id`", Engine: "InnoDB"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			result, err := ParseCreateTable(test.Input)
+			if err != nil {
+				t.Fatal("error parse create table.", err.Error())
+			}
+
+			if diff := cmp.Diff(result, test.Output); diff != "" {
+				t.Fatalf("error invalid result:%s", diff)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{
+			"/*!50100 PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (10) */",
+			"/*!50100 PARTITION BY UNKNOWN (id) */",
+			"/*!50100 PARTITION BY LIST (id) (PARTITION p0 VALUES IN (1) COMMENT = 'unclosed) */",
+		} {
+			if _, err := ParseCreateTable(input); err == nil {
+				t.Fatalf("error %s must be error.", input)
+			}
+		}
+	})
+}
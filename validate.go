@@ -0,0 +1,444 @@
+package partition
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Conflict is a problem of requested partitions found before executing statement.
+// With is name of the other partition which conflicts with Partition if exists.
+type Conflict struct {
+	Partition string
+	With      string
+	Reason    string
+}
+
+func (c *Conflict) String() string {
+	if c.Partition == "" {
+		return c.Reason
+	}
+
+	if c.With == "" {
+		return fmt.Sprintf("%s: %s", c.Partition, c.Reason)
+	}
+
+	return fmt.Sprintf("%s: %s (conflicts with %s)", c.Partition, c.Reason, c.With)
+}
+
+// ValidationError is returned by Prepare methods when requested partitions conflict with each other or with existing partitions.
+// use errors.Cause to retrieve it from returned error.
+type ValidationError struct {
+	Conflicts []*Conflict
+}
+
+func (e *ValidationError) Error() string {
+	conflicts := []string{}
+	for _, c := range e.Conflicts {
+		conflicts = append(conflicts, c.String())
+	}
+
+	return fmt.Sprintf("error invalid partitions. %s", strings.Join(conflicts, ", "))
+}
+
+// validate checks requested partitions for the action.
+// existing partitions are fetched from information_schema only when db is available.
+// dropped are partitions dropped by preceding step of plan, which are not conflicted.
+func (p *partitioner) validate(ctx context.Context, action Action, dropped, from, into []*Partition) error {
+	from, err := p.resolvePartitions(from)
+	if err != nil {
+		return errors.Wrap(err, "error resolvePartitions")
+	}
+
+	into, err = p.resolvePartitions(into)
+	if err != nil {
+		return errors.Wrap(err, "error resolvePartitions")
+	}
+
+	if r, ok := p.partBuilder.(*Range); ok {
+		into, err = normalizeRange(r, into)
+		if err != nil {
+			return errors.Wrap(err, "error normalizeRange")
+		}
+
+		if action == ActionCreate && r.catchAllPartitionName != "" {
+			into = append(into, p.catchAllPartition(r.catchAllPartitionName))
+		}
+	}
+
+	current := []*PartitionInfo{}
+	if p.db != nil && action != ActionCreate {
+		infos, err := p.PartitionsContext(ctx)
+		if err != nil {
+			return errors.Wrap(err, "error Partitions")
+		}
+
+		names := map[string]bool{}
+		for _, partition := range dropped {
+			names[partition.Name] = true
+		}
+
+		for _, info := range infos {
+			if !names[info.Name] {
+				current = append(current, info)
+			}
+		}
+	}
+
+	return validatePartitions(p.partitionType, action, current, from, into)
+}
+
+// normalizeRange returns copies of range partitions whose descriptions are written as in the statement
+func normalizeRange(r *Range, partitions []*Partition) ([]*Partition, error) {
+	normalized := make([]*Partition, 0, len(partitions))
+	for _, partition := range partitions {
+		if partition.Description == "" {
+			normalized = append(normalized, partition)
+			continue
+		}
+
+		desc, err := r.partitionValue(partition)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error partitionValue. name:%s", partition.Name)
+		}
+
+		copied := *partition
+		copied.Description = desc
+		normalized = append(normalized, &copied)
+	}
+
+	return normalized, nil
+}
+
+func validatePartitions(method string, action Action, current []*PartitionInfo, from, into []*Partition) error {
+	v := &validator{}
+	v.validateNames(action, current, from, into)
+
+	switch {
+	case strings.HasPrefix(method, PartitionTypeRange):
+		v.validateRange(action, current, from, into)
+	case strings.HasPrefix(method, PartitionTypeList):
+		v.validateList(action, current, from, into)
+	}
+
+	if 0 < len(v.conflicts) {
+		return &ValidationError{Conflicts: v.conflicts}
+	}
+
+	return nil
+}
+
+type validator struct {
+	conflicts []*Conflict
+}
+
+func (v *validator) conflict(partition, with, format string, args ...interface{}) {
+	v.conflicts = append(v.conflicts, &Conflict{
+		Partition: partition,
+		With:      with,
+		Reason:    fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validateNames(action Action, current []*PartitionInfo, from, into []*Partition) {
+	existing := map[string]bool{}
+	for _, info := range current {
+		existing[info.Name] = true
+	}
+
+	reorganized := map[string]bool{}
+	for _, partition := range from {
+		if 0 < len(current) && !existing[partition.Name] {
+			v.conflict(partition.Name, "", "partition does not exist")
+		}
+		reorganized[partition.Name] = true
+	}
+
+	names := map[string]bool{}
+	for _, partition := range into {
+		// unnamed hash and key partitions are named by mysql
+		if partition.Name == "" {
+			continue
+		}
+
+		if names[partition.Name] {
+			v.conflict(partition.Name, "", "partition name is duplicated")
+		}
+		names[partition.Name] = true
+
+		if action != ActionCreate && existing[partition.Name] && !reorganized[partition.Name] {
+			v.conflict(partition.Name, "", "partition already exists")
+		}
+	}
+}
+
+// validateRange checks upper bounds are strictly increasing through existing and requested partitions.
+// bounds which can't be compared locally such as strings and expressions are left to mysql.
+func (v *validator) validateRange(action Action, current []*PartitionInfo, from, into []*Partition) {
+	var prev *Partition
+	switch action {
+	case ActionAdd:
+		// partitions are added before catch all partition by reorganizing it
+		last := len(current) - 1
+		if 0 <= last && strings.HasPrefix(current[last].Description, CatchAllPartitionValue) {
+			last--
+		}
+		if 0 <= last {
+			prev = current[last].Partition()
+		}
+	case ActionReorganize:
+		positions := map[string]int{}
+		for i, info := range current {
+			positions[info.Name] = i
+		}
+
+		for i, partition := range from {
+			position, ok := positions[partition.Name]
+			if !ok {
+				continue
+			}
+
+			if i == 0 && 0 < position {
+				prev = current[position-1].Partition()
+			}
+
+			if 0 < i {
+				if before, ok := positions[from[i-1].Name]; ok && before+1 != position {
+					v.conflict(partition.Name, from[i-1].Name, "reorganized range partitions must be adjacent")
+				}
+			}
+		}
+	}
+
+	for _, partition := range into {
+		if prev != nil {
+			if c, ok := compareBounds(parseBound(prev.Description), parseBound(partition.Description)); ok && 0 <= c {
+				v.conflict(partition.Name, prev.Name, "VALUES LESS THAN (%s) must be greater than (%s)", partition.Description, prev.Description)
+			}
+		}
+		prev = partition
+	}
+}
+
+// validateList checks values are not duplicated in requested and remaining partitions
+func (v *validator) validateList(action Action, current []*PartitionInfo, from, into []*Partition) {
+	reorganized := map[string]bool{}
+	for _, partition := range from {
+		reorganized[partition.Name] = true
+	}
+
+	owners := map[string]string{}
+	if action != ActionCreate {
+		for _, info := range current {
+			if reorganized[info.Name] {
+				continue
+			}
+			for _, key := range listKeys(info.Description) {
+				owners[key] = info.Name
+			}
+		}
+	}
+
+	for _, partition := range into {
+		for _, key := range listKeys(partition.Description) {
+			if owner, ok := owners[key]; ok {
+				v.conflict(partition.Name, owner, "value %s is duplicated", key)
+				continue
+			}
+			owners[key] = partition.Name
+		}
+	}
+}
+
+type boundKind int
+
+const (
+	boundUnknown boundKind = iota
+	boundNumber
+	boundString
+	boundNull
+	boundMax
+)
+
+// bound is a value of partition description parsed to compare.
+// strings are compared only when they are dates because order of others depends on collation.
+type bound struct {
+	kind   boundKind
+	number *big.Rat
+	text   string
+	date   bool
+
+	// function such as UNIX_TIMESTAMP which isn't evaluated and its argument
+	function string
+	argument *bound
+}
+
+// key returns canonical text of the value
+func (b *bound) key() string {
+	switch b.kind {
+	case boundNumber:
+		return b.number.RatString()
+	case boundString:
+		return quoteString(b.text)
+	case boundNull:
+		return "NULL"
+	case boundMax:
+		return CatchAllPartitionValue
+	}
+
+	return b.text
+}
+
+var dateLayouts = []string{"2006-01-02", "2006-01-02 15:04:05", "2006-01-02 15:04:05.999999"}
+
+// parseBound parses description such as 10, '2024-01-01', 100 or TO_DAYS('2024-01-01')
+func parseBound(description string) []*bound {
+	bounds := []*bound{}
+	for _, value := range splitColumns(description) {
+		bounds = append(bounds, parseValue(value))
+	}
+
+	return bounds
+}
+
+func parseValue(value string) *bound {
+	upper := strings.ToUpper(value)
+	switch {
+	case upper == CatchAllPartitionValue:
+		return &bound{kind: boundMax}
+	case upper == "NULL":
+		return &bound{kind: boundNull}
+	case strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && 2 <= len(value):
+		text, ok := unquoteString(value)
+		if !ok {
+			break
+		}
+		// dates are compared as normalized datetime
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return &bound{kind: boundString, text: t.Format("2006-01-02 15:04:05.000000"), date: true}
+			}
+		}
+		return &bound{kind: boundString, text: text}
+	}
+
+	if n, ok := new(big.Rat).SetString(value); ok {
+		return &bound{kind: boundNumber, number: n}
+	}
+
+	// TO_DAYS('2024-01-01') is evaluated as stored in information_schema
+	if m := timeFunctionRegexp.FindStringSubmatch(value); m != nil && strings.HasSuffix(value, ")") {
+		arg := parseValue(strings.TrimSpace(value[len(m[0]) : len(value)-1]))
+		if arg.date {
+			if t, err := time.Parse("2006-01-02 15:04:05.000000", arg.text); err == nil {
+				if stored, ok := storedTimeValue(t, value); ok {
+					return parseValue(stored)
+				}
+			}
+			return &bound{kind: boundUnknown, text: strings.Join(strings.Fields(value), ""), function: strings.ToUpper(m[1]), argument: arg}
+		}
+	}
+
+	return &bound{kind: boundUnknown, text: strings.Join(strings.Fields(value), "")}
+}
+
+// unquoteString is reverse of quoteString which also accepts doubled quote
+func unquoteString(value string) (string, bool) {
+	buf := &bytes.Buffer{}
+	inner := value[1 : len(value)-1]
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == '\\' && i+1 < len(inner):
+			i++
+			switch inner[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case '0':
+				buf.WriteByte(0)
+			case 'Z':
+				buf.WriteByte(0x1a)
+			default:
+				buf.WriteByte(inner[i])
+			}
+		case c == '\'' && i+1 < len(inner) && inner[i+1] == '\'':
+			i++
+			buf.WriteByte(c)
+		case c == '\'':
+			return "", false
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return buf.String(), true
+}
+
+// compareBounds compares bounds of range partitions in column order.
+// ok is false when they can't be compared without mysql such as expressions.
+func compareBounds(a, b []*bound) (int, bool) {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0, false
+	}
+
+	for i := range a {
+		c, ok := compareBound(a[i], b[i])
+		if !ok {
+			return 0, false
+		}
+		if c != 0 {
+			return c, true
+		}
+	}
+
+	return 0, true
+}
+
+func compareBound(a, b *bound) (int, bool) {
+	switch {
+	case a.kind == boundMax && b.kind == boundMax:
+		return 0, true
+	case a.kind == boundMax:
+		return 1, true
+	case b.kind == boundMax:
+		return -1, true
+	case a.function != "" && a.function == b.function:
+		// time functions increase with their arguments
+		return compareBound(a.argument, b.argument)
+	case a.kind != b.kind:
+		return 0, false
+	case a.kind == boundNumber:
+		return a.number.Cmp(b.number), true
+	case a.kind == boundString && a.date && b.date:
+		return strings.Compare(a.text, b.text), true
+	}
+
+	return 0, false
+}
+
+// listKeys returns canonical texts of values of list partition
+func listKeys(description string) []string {
+	keys := []string{}
+	for _, value := range splitColumns(description) {
+		if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+			elems := []string{}
+			for _, b := range parseBound(value[1 : len(value)-1]) {
+				elems = append(elems, b.key())
+			}
+			keys = append(keys, fmt.Sprintf("(%s)", strings.Join(elems, ", ")))
+			continue
+		}
+		keys = append(keys, parseValue(value).key())
+	}
+
+	return keys
+}
@@ -0,0 +1,213 @@
+package partition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func Test_validatePartitions(t *testing.T) {
+	type Test struct {
+		Title     string
+		Method    string
+		Action    Action
+		Current   []*PartitionInfo
+		From      []*Partition
+		Into      []*Partition
+		Conflicts []*Conflict
+	}
+
+	ranges := []*PartitionInfo{
+		&PartitionInfo{Name: "p1", Description: "'2024-01-01'"},
+		&PartitionInfo{Name: "p2", Description: "'2024-02-01'"},
+		&PartitionInfo{Name: "p3", Description: "'2024-03-01'"},
+		&PartitionInfo{Name: "pmax", Description: "MAXVALUE"},
+	}
+
+	lists := []*PartitionInfo{
+		&PartitionInfo{Name: "p1", Description: "1,2"},
+		&PartitionInfo{Name: "p2", Description: "3"},
+	}
+
+	tests := []Test{
+		Test{
+			Title:  "range creates increasing",
+			Method: "RANGE",
+			Action: ActionCreate,
+			Into:   []*Partition{NewPartition("p1", "10", ""), NewPartition("p2", "TO_DAYS('2024-01-01')", ""), NewPartition("pmax", "MAXVALUE", "")},
+		},
+		Test{
+			Title:  "range creates not increasing",
+			Method: "RANGE",
+			Action: ActionCreate,
+			Into:   []*Partition{NewPartition("p1", "20", ""), NewPartition("p2", "10", ""), NewPartition("p2", "30", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p2", Reason: "partition name is duplicated"},
+				&Conflict{Partition: "p2", With: "p1", Reason: "VALUES LESS THAN (10) must be greater than (20)"},
+			},
+		},
+		Test{
+			Title:  "range columns compares dates and tuples",
+			Method: "RANGE COLUMNS",
+			Action: ActionCreate,
+			Into:   []*Partition{NewPartition("p1", "'2024-01-01 00:00:00', 10", ""), NewPartition("p2", "'2024-01-01', 5", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p2", With: "p1", Reason: "VALUES LESS THAN ('2024-01-01', 5) must be greater than ('2024-01-01 00:00:00', 10)"},
+			},
+		},
+		Test{
+			Title:  "range compares time functions by arguments",
+			Method: "RANGE",
+			Action: ActionCreate,
+			Into:   []*Partition{NewPartition("p1", "UNIX_TIMESTAMP('2024-02-01')", ""), NewPartition("p2", "UNIX_TIMESTAMP('2024-01-01')", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p2", With: "p1", Reason: "VALUES LESS THAN (UNIX_TIMESTAMP('2024-01-01')) must be greater than (UNIX_TIMESTAMP('2024-02-01'))"},
+			},
+		},
+		Test{
+			Title:   "range skips expressions",
+			Method:  "RANGE",
+			Action:  ActionAdd,
+			Current: []*PartitionInfo{&PartitionInfo{Name: "p1", Description: "1704067200"}},
+			Into:    []*Partition{NewPartition("p2", "UNIX_TIMESTAMP('2024-02-01')", ""), NewPartition("p3", "(10+1)", ""), NewPartition("p4", "100", "")},
+		},
+		Test{
+			Title:  "range columns skips strings",
+			Method: "RANGE COLUMNS",
+			Action: ActionCreate,
+			Into:   []*Partition{NewPartition("p1", "'g'", ""), NewPartition("p2", "'n'", ""), NewPartition("pmax", "MAXVALUE", "")},
+		},
+		Test{
+			Title:   "range adds below current max",
+			Method:  "RANGE COLUMNS",
+			Action:  ActionAdd,
+			Current: ranges,
+			Into:    []*Partition{NewPartition("p4", "'2024-02-15'", ""), NewPartition("p3", "'2024-04-01'", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p3", Reason: "partition already exists"},
+				&Conflict{Partition: "p4", With: "p3", Reason: "VALUES LESS THAN ('2024-02-15') must be greater than ('2024-03-01')"},
+			},
+		},
+		Test{
+			Title:   "range reorganizes",
+			Method:  "RANGE COLUMNS",
+			Action:  ActionReorganize,
+			Current: ranges,
+			From:    []*Partition{NewPartition("p1", "", ""), NewPartition("p3", "", "")},
+			Into:    []*Partition{NewPartition("p0", "'2023-01-01'", ""), NewPartition("p2", "'2024-03-01'", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p2", Reason: "partition already exists"},
+				&Conflict{Partition: "p3", With: "p1", Reason: "reorganized range partitions must be adjacent"},
+			},
+		},
+		Test{
+			Title:   "range reorganizes below previous partition",
+			Method:  "RANGE COLUMNS",
+			Action:  ActionReorganize,
+			Current: ranges,
+			From:    []*Partition{NewPartition("p3", "", "")},
+			Into:    []*Partition{NewPartition("p25", "'2024-01-15'", ""), NewPartition("p3", "'2024-03-01'", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p25", With: "p2", Reason: "VALUES LESS THAN ('2024-01-15') must be greater than ('2024-02-01')"},
+			},
+		},
+		Test{
+			Title:   "list adds overlapping values",
+			Method:  "LIST",
+			Action:  ActionAdd,
+			Current: lists,
+			Into:    []*Partition{NewPartition("p3", "4, 02", ""), NewPartition("p4", "5, 4", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p3", With: "p1", Reason: "value 2 is duplicated"},
+				&Conflict{Partition: "p4", With: "p3", Reason: "value 4 is duplicated"},
+			},
+		},
+		Test{
+			Title:   "list reorganizes moves values",
+			Method:  "LIST",
+			Action:  ActionReorganize,
+			Current: lists,
+			From:    []*Partition{NewPartition("p1", "", ""), NewPartition("p9", "", "")},
+			Into:    []*Partition{NewPartition("p1", "1", ""), NewPartition("p3", "2, 3", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p9", Reason: "partition does not exist"},
+				&Conflict{Partition: "p3", With: "p2", Reason: "value 3 is duplicated"},
+			},
+		},
+		Test{
+			Title:  "list columns tuples",
+			Method: "LIST COLUMNS",
+			Action: ActionCreate,
+			Into:   []*Partition{NewPartition("p1", "(1, 'a'), (NULL, 'b')", ""), NewPartition("p2", "(1,'A'), (null,'b')", "")},
+			Conflicts: []*Conflict{
+				&Conflict{Partition: "p2", With: "p1", Reason: "value (NULL, 'b') is duplicated"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			err := validatePartitions(test.Method, test.Action, test.Current, test.From, test.Into)
+			if len(test.Conflicts) == 0 {
+				if err != nil {
+					t.Fatal("error validate.", err.Error())
+				}
+				return
+			}
+
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("error invalid error: %v", err)
+			}
+
+			if diff := cmp.Diff(verr.Conflicts, test.Conflicts); diff != "" {
+				t.Fatalf("error invalid conflicts:%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	p := NewRangePartitioner(nil, "test", "id")
+
+	_, err := p.PrepareCreates(NewPartition("p1", "20", ""), NewPartition("p2", "10", ""))
+	verr, ok := errors.Cause(err).(*ValidationError)
+	if !ok {
+		t.Fatalf("error invalid error: %v", err)
+	}
+
+	expect := "error invalid partitions. p2: VALUES LESS THAN (10) must be greater than (20) (conflicts with p1)"
+	if diff := cmp.Diff(verr.Error(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	columns := NewRangePartitioner(nil, "test", "created_at", Type("range columns"))
+	if _, err := columns.PrepareCreates(NewPartition("p1", "2010-01-01", ""), NewPartition("p2", "2010-02-01", "")); err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	names := NewRangePartitioner(nil, "test", "name", Type("range columns"))
+	if _, err := names.PrepareCreates(NewPartition("p1", "g", ""), NewPartition("p2", "n", "")); err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	if _, err := p.PrepareCreates(NewPartition("p1", "(10+1)", ""), NewPartition("p2", "100", "")); err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	_, err = columns.PrepareCreates(NewPartition("p1", "2010-02-01", ""), NewPartition("p2", "2010-01-01", ""))
+	verr, ok = errors.Cause(err).(*ValidationError)
+	if !ok {
+		t.Fatalf("error invalid error: %v", err)
+	}
+
+	expect = "error invalid partitions. p2: VALUES LESS THAN ('2010-01-01') must be greater than ('2010-02-01') (conflicts with p1)"
+	if diff := cmp.Diff(verr.Error(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	if _, err := NewListPartitioner(nil, "test", "id").PrepareAdds(NewListPartitionValues("p1", 1, 2), NewListPartitionValues("p2", int64(2))); err == nil {
+		t.Fatal("error duplicated list value must be error.")
+	}
+}
@@ -0,0 +1,325 @@
+package partition
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Expr is raw SQL expression written as partition value as is such as TO_DAYS('2010-01-01')
+type Expr string
+
+// MaxValue is upper bound of catch all range partition
+const MaxValue = Expr(CatchAllPartitionValue)
+
+// Tuple is value of multiple columns for LIST COLUMNS partition such as (1, 'a')
+type Tuple []interface{}
+
+// toDaysOfUnixEpoch is TO_DAYS('1970-01-01')
+const toDaysOfUnixEpoch = 719528
+
+var timeFunctionRegexp *regexp.Regexp
+
+func init() {
+	timeFunctionRegexp = regexp.MustCompile("(?i)^\\s*(TO_DAYS|TO_SECONDS|UNIX_TIMESTAMP|YEAR)\\s*\\(")
+}
+
+// NewRangePartitionTime is range partition whose values are less than t.
+// t is written as date when it is midnight, otherwise as datetime in its location.
+// For RANGE partitioned by TO_DAYS, TO_SECONDS, UNIX_TIMESTAMP or YEAR, t is wrapped with the function.
+func NewRangePartitionTime(name string, t time.Time) *Partition {
+	return NewRangePartitionValues(name, t)
+}
+
+// NewRangePartitionInt is range partition whose values are less than n
+func NewRangePartitionInt(name string, n int64) *Partition {
+	return NewRangePartitionValues(name, n)
+}
+
+// NewRangePartitionValues is range partition whose values are less than values.
+// values are typed values such as int64, string, time.Time, Expr or MaxValue.
+// For RANGE COLUMNS partition, values are specified for each column.
+func NewRangePartitionValues(name string, values ...interface{}) *Partition {
+	return &Partition{
+		Name:   name,
+		Values: values,
+	}
+}
+
+// NewListPartitionValues is list partition which has values.
+// values are typed values such as int64, string, time.Time, Expr or nil for NULL.
+// For LIST COLUMNS partition with multiple columns, each value is Tuple.
+func NewListPartitionValues(name string, values ...interface{}) *Partition {
+	return &Partition{
+		Name:   name,
+		Values: values,
+	}
+}
+
+// resolvePartitions returns copies of partitions whose Description is built from typed Values
+func (p *partitioner) resolvePartitions(partitions []*Partition) ([]*Partition, error) {
+	return resolvePartitions(partitions, p.partitionType, p.expression)
+}
+
+func resolvePartitions(partitions []*Partition, method, expression string) ([]*Partition, error) {
+	resolved := make([]*Partition, 0, len(partitions))
+	for _, partition := range partitions {
+		if len(partition.Values) == 0 {
+			if err := validateDescriptionArity(partition.Description, method, expression); err != nil {
+				return nil, errors.Wrapf(err, "error validateDescriptionArity. name:%s", partition.Name)
+			}
+			resolved = append(resolved, partition)
+			continue
+		}
+
+		if err := validateArity(partition.Values, method, expression); err != nil {
+			return nil, errors.Wrapf(err, "error validateArity. name:%s", partition.Name)
+		}
+
+		desc, err := buildValues(partition.Values, method, expression)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error buildValues. name:%s", partition.Name)
+		}
+
+		copied := *partition
+		copied.Description = desc
+		resolved = append(resolved, &copied)
+	}
+
+	return resolved, nil
+}
+
+func buildValues(values []interface{}, method, expression string) (string, error) {
+	literals := []string{}
+	for _, value := range values {
+		literal, err := buildValue(value, method, expression)
+		if err != nil {
+			return "", err
+		}
+		literals = append(literals, literal)
+	}
+
+	return strings.Join(literals, ", "), nil
+}
+
+// validateArity checks number of values matches number of partitioning columns.
+// range partition has a value for each column and list partition has tuples when it has multiple columns.
+func validateArity(values []interface{}, method, expression string) error {
+	count := columnCount(method, expression)
+	if strings.HasPrefix(method, PartitionTypeRange) {
+		if len(values) != count {
+			return fmt.Errorf("error range partition needs %d values but %d values are specified", count, len(values))
+		}
+		return nil
+	}
+
+	for _, value := range values {
+		tuple, ok := value.(Tuple)
+		if !ok {
+			tuple = Tuple{value}
+		}
+
+		if len(tuple) != count {
+			return fmt.Errorf("error list partition needs tuple of %d values but %d values are specified", count, len(tuple))
+		}
+	}
+
+	return nil
+}
+
+// validateDescriptionArity is validateArity for description such as '2024-01-01', 100 or (1, 'a'), (2, 'b')
+func validateDescriptionArity(description, method, expression string) error {
+	if description == "" || columnCount(method, expression) == 1 {
+		return nil
+	}
+
+	values := []interface{}{}
+	for _, value := range splitColumns(description) {
+		if strings.HasPrefix(method, PartitionTypeList) && strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+			tuple := Tuple{}
+			for _, elem := range splitColumns(value[1 : len(value)-1]) {
+				tuple = append(tuple, Expr(elem))
+			}
+			values = append(values, tuple)
+			continue
+		}
+		values = append(values, Expr(value))
+	}
+
+	return validateArity(values, method, expression)
+}
+
+// buildValue returns SQL literal of value
+func buildValue(value interface{}, method, expression string) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		if strings.HasPrefix(method, PartitionTypeRange) {
+			return "", fmt.Errorf("error NULL is not allowed for range partition")
+		}
+		return "NULL", nil
+	case Expr:
+		if v == MaxValue && !strings.HasPrefix(method, PartitionTypeRange) {
+			return "", fmt.Errorf("error MAXVALUE is allowed only for range partition")
+		}
+		if err := validateExpression(string(v)); err != nil {
+			return "", err
+		}
+		return string(v), nil
+	case Tuple:
+		if !strings.HasPrefix(method, PartitionTypeList) {
+			return "", fmt.Errorf("error tuple is allowed only for list columns partition")
+		}
+		for _, elem := range v {
+			if _, ok := elem.(Tuple); ok {
+				return "", fmt.Errorf("error tuple can't be nested")
+			}
+		}
+		values, err := buildValues(v, method, expression)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s)", values), nil
+	case string:
+		return quoteString(v), nil
+	case []byte:
+		return quoteString(string(v)), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		return buildTimeValue(v, method, expression)
+	}
+
+	return "", fmt.Errorf("error unsupported partition value type %T", value)
+}
+
+// buildTimeValue returns t as string literal for COLUMNS partitioning.
+// Other partitioning needs integer, so t is wrapped with the function of partitioning expression.
+func buildTimeValue(t time.Time, method, expression string) (string, error) {
+	literal := quoteString(formatTime(t))
+	if strings.HasSuffix(method, " COLUMNS") {
+		return literal, nil
+	}
+
+	if err := CheckTimeValue(method, expression); err != nil {
+		return "", err
+	}
+
+	m := timeFunctionRegexp.FindStringSubmatch(expression)
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(m[1]), literal), nil
+}
+
+// CheckTimeValue returns error when partitioning by the method and expression can't have time value.
+// time value needs RANGE COLUMNS or LIST COLUMNS, or expression by TO_DAYS, TO_SECONDS, UNIX_TIMESTAMP or YEAR.
+func CheckTimeValue(method, expression string) error {
+	if strings.HasSuffix(method, " COLUMNS") || timeFunctionRegexp.MatchString(expression) {
+		return nil
+	}
+
+	return fmt.Errorf("error time value needs COLUMNS partitioning or expression by TO_DAYS, TO_SECONDS, UNIX_TIMESTAMP or YEAR. expression:%s", expression)
+}
+
+func formatTime(t time.Time) string {
+	switch {
+	case t.Nanosecond() != 0:
+		return t.Format("2006-01-02 15:04:05.000000")
+	case t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0:
+		return t.Format("2006-01-02 15:04:05")
+	}
+
+	return t.Format("2006-01-02")
+}
+
+// storedTimeValue returns t evaluated by the function of partitioning expression as stored in information_schema.
+// UNIX_TIMESTAMP is not evaluated because it depends on time zone of the session.
+func storedTimeValue(t time.Time, expression string) (string, bool) {
+	m := timeFunctionRegexp.FindStringSubmatch(expression)
+	if m == nil {
+		return "", false
+	}
+
+	days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix()/int64(24*time.Hour/time.Second) + toDaysOfUnixEpoch
+	switch strings.ToUpper(m[1]) {
+	case "TO_DAYS":
+		return strconv.FormatInt(days, 10), true
+	case "TO_SECONDS":
+		return strconv.FormatInt(days*86400+int64(t.Hour()*3600+t.Minute()*60+t.Second()), 10), true
+	case "YEAR":
+		return strconv.Itoa(t.Year()), true
+	}
+
+	return "", false
+}
+
+// quoteColumns returns quoted column list for COLUMNS partitioning
+func quoteColumns(columns []string) string {
+	quoted := []string{}
+	for _, column := range columns {
+		quoted = append(quoted, quoteIdentifier(column))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// columnCount returns number of partitioning columns. it is always 1 except COLUMNS partitioning.
+func columnCount(method, expression string) int {
+	if !strings.HasSuffix(method, " COLUMNS") {
+		return 1
+	}
+
+	if columns := splitColumns(expression); 1 < len(columns) {
+		return len(columns)
+	}
+
+	return 1
+}
+
+func (p *partitioner) columnCount() int {
+	return columnCount(p.partitionType, p.expression)
+}
+
+// splitColumns splits column list by comma outside of quotes and brackets
+func splitColumns(expression string) []string {
+	columns := []string{}
+	var quote rune
+	depth, start := 0, 0
+	for i, r := range expression {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '`' || r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			columns = append(columns, strings.TrimSpace(expression[start:i]))
+			start = i + 1
+		}
+	}
+
+	if last := strings.TrimSpace(expression[start:]); last != "" {
+		columns = append(columns, last)
+	}
+
+	return columns
+}
+
+func (p *partitioner) checkTimeValue() error {
+	return CheckTimeValue(p.partitionType, p.expression)
+}
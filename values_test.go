@@ -0,0 +1,206 @@
+package partition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_buildValue(t *testing.T) {
+	type Test struct {
+		Value      interface{}
+		Method     string
+		Expression string
+		Output     string
+	}
+
+	tests := []Test{
+		Test{Value: int64(-10), Method: "RANGE", Expression: "id", Output: "-10"},
+		Test{Value: uint8(3), Method: "LIST", Expression: "id", Output: "3"},
+		Test{Value: 1.5, Method: "RANGE COLUMNS", Expression: "score", Output: "1.5"},
+		Test{Value: "it's", Method: "LIST COLUMNS", Expression: "name", Output: "'it''s'"},
+		Test{Value: nil, Method: "LIST", Expression: "id", Output: "NULL"},
+		Test{Value: MaxValue, Method: "RANGE", Expression: "id", Output: "MAXVALUE"},
+		Test{Value: Expr("TO_DAYS('2010-01-01')"), Method: "RANGE", Expression: "TO_DAYS(created_at)", Output: "TO_DAYS('2010-01-01')"},
+		Test{Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Method: "RANGE COLUMNS", Expression: "created_at", Output: "'2024-01-01'"},
+		Test{Value: time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC), Method: "RANGE COLUMNS", Expression: "created_at", Output: "'2024-01-01 12:30:00'"},
+		Test{Value: time.Date(2024, 1, 1, 0, 0, 0, 500000000, time.UTC), Method: "RANGE COLUMNS", Expression: "created_at", Output: "'2024-01-01 00:00:00.500000'"},
+		Test{Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Method: "RANGE", Expression: "to_days(`created_at`)", Output: "TO_DAYS('2024-01-01')"},
+		Test{Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Method: "RANGE", Expression: "UNIX_TIMESTAMP(created_at)", Output: "UNIX_TIMESTAMP('2024-01-01')"},
+	}
+
+	for _, test := range tests {
+		output, err := buildValue(test.Value, test.Method, test.Expression)
+		if err != nil {
+			t.Fatal("error build value.", err.Error())
+		}
+
+		if diff := cmp.Diff(output, test.Output); diff != "" {
+			t.Fatalf("error invalid result:%s", diff)
+		}
+	}
+
+	invalids := []Test{
+		Test{Value: nil, Method: "RANGE", Expression: "id"},
+		Test{Value: MaxValue, Method: "LIST", Expression: "id"},
+		Test{Value: Expr("1); DROP TABLE test"), Method: "RANGE", Expression: "id"},
+		Test{Value: time.Now(), Method: "RANGE", Expression: "created_at"},
+		Test{Value: struct{}{}, Method: "LIST", Expression: "id"},
+	}
+
+	for _, test := range invalids {
+		if _, err := buildValue(test.Value, test.Method, test.Expression); err == nil {
+			t.Fatalf("error %#v must be invalid.", test.Value)
+		}
+	}
+}
+
+func Test_storedTimeValue(t *testing.T) {
+	tests := map[string]string{
+		"TO_DAYS(created_at)":    "734138",
+		"to_seconds(created_at)": "63429523200",
+		"YEAR(created_at)":       "2010",
+	}
+
+	for expression, expect := range tests {
+		value, ok := storedTimeValue(time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), expression)
+		if !ok {
+			t.Fatalf("error %s must be evaluated.", expression)
+		}
+
+		if diff := cmp.Diff(value, expect); diff != "" {
+			t.Fatalf("error invalid result:%s", diff)
+		}
+	}
+
+	if _, ok := storedTimeValue(time.Now(), "UNIX_TIMESTAMP(created_at)"); ok {
+		t.Fatal("error UNIX_TIMESTAMP must not be evaluated.")
+	}
+}
+
+func TestTypedPartitions(t *testing.T) {
+	r := NewRangePartitioner(nil, "events", "TO_DAYS(created_at)", CatchAllPartitionName("pmax"))
+	h, err := r.PrepareCreates(
+		NewRangePartitionTime("p201001", time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)),
+		NewRangePartitionInt("p201002", 734169),
+	)
+	if err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	expect := "ALTER TABLE `events` PARTITION BY RANGE (TO_DAYS(created_at)) (PARTITION `p201001` VALUES LESS THAN (TO_DAYS('2010-01-01')), PARTITION `p201002` VALUES LESS THAN (734169), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	l := NewListPartitioner(nil, "events", "region", Type("list columns"))
+	h, err = l.PrepareAdds(NewListPartitionValues("p1", "tokyo", "o'saka", nil))
+	if err != nil {
+		t.Fatal("error prepare adds.", err.Error())
+	}
+
+	expect = "ALTER TABLE `events` ADD PARTITION (PARTITION `p1` VALUES IN ('tokyo', 'o''saka', NULL))"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+}
+
+func TestColumnsPartitions(t *testing.T) {
+	r := NewRangeColumnsPartitioner(nil, "events", []string{"created_at", "region_id"}, CatchAllPartitionName("pmax"))
+	h, err := r.PrepareCreates(
+		NewRangePartitionValues("p1", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 100),
+		NewPartition("p2", "2024-02-01, 200", ""),
+	)
+	if err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	expect := "ALTER TABLE `events` PARTITION BY RANGE COLUMNS (`created_at`, `region_id`) (PARTITION `p1` VALUES LESS THAN ('2024-01-01', 100), PARTITION `p2` VALUES LESS THAN ('2024-02-01', 200), PARTITION `pmax` VALUES LESS THAN (MAXVALUE, MAXVALUE))"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	h, err = r.PrepareReorganizeCatchAllPartition(NewRangePartitionValues("p3", "2024-03-01", MaxValue))
+	if err != nil {
+		t.Fatal("error prepare reorganize catch all partition.", err.Error())
+	}
+
+	expect = "ALTER TABLE `events` REORGANIZE PARTITION `pmax` INTO (PARTITION `p3` VALUES LESS THAN ('2024-03-01', MAXVALUE), PARTITION `pmax` VALUES LESS THAN (MAXVALUE, MAXVALUE))"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	if _, err := r.PrepareAdds(NewRangePartitionValues("p3", "2024-03-01")); err == nil {
+		t.Fatal("error missing column value must be error.")
+	}
+
+	if _, err := r.PrepareAdds(NewPartition("p3", "'2024-03-01', 300, 1", "")); err == nil {
+		t.Fatal("error extra column value must be error.")
+	}
+
+	l := NewListColumnsPartitioner(nil, "events", []string{"region_id", "kind"})
+	h, err = l.PrepareCreates(
+		NewListPartitionValues("p1", Tuple{1, "a"}, Tuple{2, "b"}),
+		NewPartition("p2", "(3, 'c'), (NULL, 'd')", ""),
+	)
+	if err != nil {
+		t.Fatal("error prepare creates.", err.Error())
+	}
+
+	expect = "ALTER TABLE `events` PARTITION BY LIST COLUMNS (`region_id`, `kind`) (PARTITION `p1` VALUES IN ((1, 'a'), (2, 'b')), PARTITION `p2` VALUES IN ((3, 'c'), (NULL, 'd')))"
+	if diff := cmp.Diff(h.Statement(), expect); diff != "" {
+		t.Fatalf("error invalid result:%s", diff)
+	}
+
+	invalids := []*Partition{
+		NewListPartitionValues("p3", Tuple{3}),
+		NewListPartitionValues("p3", 3, 4),
+		NewListPartitionValues("p3", Tuple{3, Tuple{4, 5}}),
+		NewPartition("p3", "(3, 'c', 1)", ""),
+	}
+
+	for _, partition := range invalids {
+		if _, err := l.PrepareAdds(partition); err == nil {
+			t.Fatalf("error arity of %s must be invalid.", partition.Name)
+		}
+	}
+
+	if _, err := NewListPartitioner(nil, "events", "region_id").PrepareAdds(NewListPartitionValues("p1", Tuple{1})); err != nil {
+		t.Fatal("error tuple of single column must be valid.", err.Error())
+	}
+}
+
+func Test_splitColumns(t *testing.T) {
+	tests := map[string][]string{
+		"created_at":                 []string{"created_at"},
+		"`a`, `b,c`":                 []string{"`a`", "`b,c`"},
+		"'2024-01-01', 100":          []string{"'2024-01-01'", "100"},
+		"(1, 'a,b'), (2, 'c')":       []string{"(1, 'a,b')", "(2, 'c')"},
+		"TO_DAYS('2024-01-01'), 10 ": []string{"TO_DAYS('2024-01-01')", "10"},
+	}
+
+	for input, expect := range tests {
+		if diff := cmp.Diff(splitColumns(input), expect); diff != "" {
+			t.Fatalf("error invalid result:%s", diff)
+		}
+	}
+}
+
+func TestCheckTimeValue(t *testing.T) {
+	for _, method := range []string{"RANGE COLUMNS", "LIST COLUMNS"} {
+		if err := CheckTimeValue(method, "created_at"); err != nil {
+			t.Fatal("error check time value.", err.Error())
+		}
+	}
+
+	for _, expression := range []string{"TO_DAYS(created_at)", "unix_timestamp (created_at)", "YEAR(created_at)"} {
+		if err := CheckTimeValue("RANGE", expression); err != nil {
+			t.Fatal("error check time value.", err.Error())
+		}
+	}
+
+	if err := CheckTimeValue("RANGE", "id"); err == nil {
+		t.Fatal("error expression without time function must be error.")
+	}
+}